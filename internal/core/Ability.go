@@ -0,0 +1,269 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// AbilityKind is the printed ability icon.
+type AbilityKind string
+
+const (
+	AbilityACT  AbilityKind = "ACT"
+	AbilityAUTO AbilityKind = "AUTO"
+	AbilityCONT AbilityKind = "CONT"
+)
+
+// LimitScope is how long usage of a limited ability is counted.
+type LimitScope string
+
+const (
+	LimitNone  LimitScope = ""
+	LimitTurn  LimitScope = "Turn"
+	LimitFight LimitScope = "Fight"
+)
+
+// UsageLimit is the "1/Turn" or "1/Fight" part of an ability, Count defaults to 1.
+type UsageLimit struct {
+	Scope LimitScope
+	Count int
+}
+
+func (limit UsageLimit) max() int {
+	if limit.Count <= 0 {
+		return 1
+	}
+	return limit.Count
+}
+
+func (limit UsageLimit) String() string {
+	if limit.Scope == LimitNone {
+		return ""
+	}
+	return strconv.Itoa(limit.max()) + "/" + string(limit.Scope)
+}
+
+// Ability is an executable card ability. AUTO abilities wait for their Timing,
+// OnSelf restricts them to events about the card itself ("when this unit attacks").
+type Ability struct {
+	Kind        AbilityKind
+	Description string
+	Timing      string
+	OnSelf      bool
+	Condition   Condition
+	Cost        Cost
+	Effect      EffectAction
+	Limit       UsageLimit
+}
+
+// emit announces an event, subject being the card it is about (or nil). The AUTO abilities
+// it triggers wait in EventQueue and are resolved at once, or after the ability being
+// resolved when the event happens during a resolution.
+func (party *Party) emit(timing string, subject *Card) {
+	subjectID := ""
+	if subject != nil {
+		subjectID = subject.ID
+	}
+
+	for _, playerIndex := range party.activePlayerOrder() {
+		player := &party.Players[playerIndex]
+		onField := abilityHolders(player)
+		holders := onField
+		// "When this card is retired/bound/..." abilities trigger from the zone the card went to
+		if subject != nil && !containsCard(onField, subject) && party.ownerOf(subject) == player {
+			holders = append(holders, subject)
+		}
+		for _, card := range holders {
+			for i := range card.Abilities {
+				ability := &card.Abilities[i]
+				if ability.Kind != AbilityAUTO || ability.Timing != timing {
+					continue
+				}
+				if ability.OnSelf && card.ID != subjectID {
+					continue
+				}
+				if !ability.OnSelf && !containsCard(onField, card) {
+					continue
+				}
+				if ability.Condition != nil && !ability.Condition(party, player, card) {
+					continue
+				}
+				if !party.CanUseAbility(player, card, i) {
+					continue
+				}
+				party.EventQueue = append(party.EventQueue, party.abilityEvent(timing, player, card, i))
+			}
+		}
+	}
+
+	party.resolveEvents()
+}
+
+// abilityEvent wraps the cost payment and effect of an ability into an Event.
+func (party *Party) abilityEvent(timing string, player *Player, card *Card, index int) Event {
+	ability := &card.Abilities[index]
+	description := card.Name + " [" + string(ability.Kind) + "] " + ability.Description
+	return Event{
+		EventType:   timing,
+		Origin:      card.ID,
+		PlayerIndex: party.playerIndex(player),
+		Description: description,
+		FuncCall: func() {
+			// The limit may have been reached while the ability was waiting
+			if !party.CanUseAbility(player, card, index) {
+				return
+			}
+			if !ability.Cost.IsZero() && !party.payOptionalCost(player, card, ability) {
+				return
+			}
+			party.recordAbilityUse(player, card, index)
+			fmt.Printf("Ability: %s [%s] %s\n", card.Name, ability.Kind, ability.Description)
+			// Only an ability that resolves is logged, before its effect logs what it does
+			party.logCard(LogAbility, player, card, description)
+			if ability.Effect != nil {
+				ability.Effect(party, player, card)
+			}
+		},
+	}
+}
+
+// payOptionalCost asks the player whether to pay the cost of an AUTO ability ("you may pay")
+// and pays it, reporting whether it was paid.
+func (party *Party) payOptionalCost(player *Player, card *Card, ability *Ability) bool {
+	if !party.CanPay(player, ability.Cost) {
+		return false
+	}
+	prompt := card.Name + ": pay " + ability.Cost.String() + "? " + ability.Description
+	if len(party.choose(player, Choice{Kind: ChoiceOptional, Prompt: prompt, Options: []string{"Pay"}, Min: 0, Max: 1})) == 0 {
+		return false
+	}
+	return party.PayCost(player, ability.Cost)
+}
+
+// ActivateAbility plays an [ACT] ability of one of the player's cards.
+func (party *Party) ActivateAbility(player *Player, card *Card, index int) error {
+	if card == nil || index < 0 || index >= len(card.Abilities) {
+		return errors.New("no such ability")
+	}
+	ability := &card.Abilities[index]
+	if ability.Kind != AbilityACT {
+		return errors.New("not an ACT ability")
+	}
+	if !party.CanUseAbility(player, card, index) {
+		return errors.New("usage limit reached (" + ability.Limit.String() + ")")
+	}
+	if ability.Condition != nil && !ability.Condition(party, player, card) {
+		return errors.New("condition not met")
+	}
+	if !party.CanPay(player, ability.Cost) {
+		return errors.New("cannot pay " + ability.Cost.String())
+	}
+
+	party.EventQueue = append(party.EventQueue, party.abilityEvent(string(AbilityACT), player, card, index))
+	party.resolveEvents()
+	return nil
+}
+
+// usageKey identifies what a limited ability's usage is counted against:
+// the card instance for 1/Turn, and the player and card name for 1/Fight,
+// since 1/Fight is shared by every card with the same name.
+func (party *Party) usageKey(player *Player, card *Card, index int) string {
+	if card.Abilities[index].Limit.Scope == LimitFight {
+		return strconv.Itoa(party.playerIndex(player)) + ":" + card.Name + "/" + strconv.Itoa(index)
+	}
+	return card.ID + "#" + strconv.Itoa(card.Instance) + "/" + strconv.Itoa(index)
+}
+
+func (party *Party) usageCounters(scope LimitScope) map[string]int {
+	if scope == LimitFight {
+		if party.FightCounters == nil {
+			party.FightCounters = map[string]int{}
+		}
+		return party.FightCounters
+	}
+	if party.TurnCounters == nil {
+		party.TurnCounters = map[string]int{}
+	}
+	return party.TurnCounters
+}
+
+// CanUseAbility checks the ability has not reached its 1/Turn or 1/Fight limit.
+func (party *Party) CanUseAbility(player *Player, card *Card, index int) bool {
+	limit := card.Abilities[index].Limit
+	if limit.Scope == LimitNone {
+		return true
+	}
+	return party.usageCounters(limit.Scope)[party.usageKey(player, card, index)] < limit.max()
+}
+
+func (party *Party) recordAbilityUse(player *Player, card *Card, index int) {
+	limit := card.Abilities[index].Limit
+	if limit.Scope == LimitNone {
+		return
+	}
+	party.usageCounters(limit.Scope)[party.usageKey(player, card, index)]++
+}
+
+// leaveField makes the card a new instance once it leaves the field: its 1/Turn usage,
+// rest state and modifiers do not follow it.
+func (party *Party) leaveField(card *Card) {
+	card.Instance++
+	card.Rested = false
+
+	kept := party.Modifiers[:0]
+	for _, modifier := range party.Modifiers {
+		if modifier.CardID != card.ID {
+			kept = append(kept, modifier)
+		}
+	}
+	party.Modifiers = kept
+	party.removeSourceReplacements(card)
+}
+
+// activePlayerOrder lists player indices starting with the turn player.
+func (party *Party) activePlayerOrder() []int {
+	order := []int{}
+	if len(party.Players) == 0 {
+		return order
+	}
+	turnPlayer := 0
+	if party.Turn > 0 {
+		turnPlayer = (party.Turn - 1) % len(party.Players)
+	}
+	for i := range party.Players {
+		order = append(order, (turnPlayer+i)%len(party.Players))
+	}
+	return order
+}
+
+// turnPlayer returns the player whose turn it is, the first player before the game starts.
+func (party *Party) turnPlayer() *Player {
+	order := party.activePlayerOrder()
+	if len(order) == 0 {
+		return nil
+	}
+	return &party.Players[order[0]]
+}
+
+// abilityHolders are the cards whose abilities can trigger: units on circles, guardians and crests.
+// Locked units have no abilities.
+func abilityHolders(player *Player) []*Card {
+	cards := []*Card{}
+	for _, id := range player.OccupiedCircles(AllCircles) {
+		if card := player.Circle(id).TopCard; !card.Locked {
+			cards = append(cards, card)
+		}
+	}
+	cards = append(cards, player.GuardZone...)
+	return append(cards, player.CrestZone...)
+}
+
+func containsCard(cards []*Card, card *Card) bool {
+	for _, c := range cards {
+		if c == card {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,58 @@
+package core
+
+import "testing"
+
+// The cost of an AUTO ability is only paid when the player accepts.
+func TestAutoAbilityCostIsOptional(t *testing.T) {
+	const timing = "TEST_TIMING"
+	for _, pay := range []bool{false, true} {
+		resolved := 0
+		script := CardScript{Key: "Paying Card", Abilities: func() []Ability {
+			return []Ability{{
+				Kind:        AbilityAUTO,
+				Description: "Energy Blast 1: resolves",
+				Timing:      timing,
+				Cost:        Cost{EnergyBlast: 1},
+				Effect: func(party *Party, player *Player, source *Card) bool {
+					resolved++
+					return true
+				},
+			}}
+		}}
+		party, card := sampleBoard(script.Key, script)
+		player := &party.Players[0]
+		asked := 0
+		party.Decide = func(playerIndex int, choice Choice) []int {
+			asked++
+			if choice.Kind != ChoiceOptional || choice.Min != 0 {
+				t.Errorf("asked %+v, want an optional choice", choice)
+			}
+			if pay {
+				return []int{0}
+			}
+			return []int{}
+		}
+		energy := player.Energy
+		history := len(party.History)
+
+		party.emit(timing, card)
+
+		wantEnergy, wantResolved := energy, 0
+		if pay {
+			wantEnergy, wantResolved = energy-1, 1
+		}
+		if asked != 1 || player.Energy != wantEnergy || resolved != wantResolved {
+			t.Errorf("pay %t: asked %d time(s), energy %d, resolved %d; want 1, %d, %d", pay, asked, player.Energy, resolved, wantEnergy, wantResolved)
+		}
+		// The ability is only logged when it resolves
+		logged := 0
+		for _, entry := range party.History[history:] {
+			if entry.Action == LogAbility && entry.Card == card.ID {
+				logged++
+			}
+		}
+		if logged != wantResolved {
+			t.Errorf("pay %t: ability logged %d time(s), want %d", pay, logged, wantResolved)
+		}
+	}
+}
@@ -0,0 +1,399 @@
+package core
+
+import "fmt"
+
+// SentinelsPerBattle is how many sentinels a player may call to (GC) in one battle.
+const SentinelsPerBattle = 1
+
+// SentinelCost is paid to activate a sentinel placed on (GC).
+var SentinelCost = Cost{Discard: 1}
+
+// Battle timings, passed to checkEffects during the battle sequence.
+const (
+	TimingBoosted     = "WHEN_BOOSTED"
+	TimingBoosting    = "WHEN_BOOSTING"
+	TimingAttack      = "WHEN_ATTACKS"
+	TimingAttacked    = "WHEN_ATTACKED"
+	TimingDriveCheck  = "DRIVE_CHECK"
+	TimingDamageCheck = "DAMAGE_CHECK"
+	TimingHit         = "WHEN_HITS"
+	TimingEndOfBattle = "END_OF_BATTLE"
+)
+
+// Battle holds the state of the attack being resolved, Party.Battle is nil outside of a battle.
+type Battle struct {
+	Attacker       *Card
+	AttackerCircle CircleID
+	Target         *Card
+	TargetCircle   CircleID
+	Booster        *Card
+	BoosterCircle  CircleID
+	Guardians      []*Card
+	Hit            bool
+	// CannotBeHit holds the IDs of units that cannot be hit until end of this battle
+	CannotBeHit map[string]bool
+	Sentinels   int
+}
+
+// PreventHit makes the card impossible to hit until end of the current battle.
+func (party *Party) PreventHit(card *Card) {
+	if party.Battle != nil && card != nil {
+		party.Battle.CannotBeHit[card.ID] = true
+	}
+}
+
+// isSentinel recognises perfect guards by their Sentinel skill.
+func isSentinel(card *Card) bool {
+	return card.HasSkill(SkillSentinel)
+}
+
+// baseDriveCount is the printed drive of a unit: its drive skill, else twin drive from grade 3.
+func baseDriveCount(card *Card) int {
+	switch {
+	case card.HasSkill(SkillTripleDrive):
+		return 3
+	case card.HasSkill(SkillTwinDrive):
+		return 2
+	case card.HasSkill(SkillDrive):
+		return 1
+	case card.Grade >= 3:
+		return 2
+	}
+	return 1
+}
+
+// DriveCount is the number of drive checks the player's vanguard performs, modifiers included.
+func (party *Party) DriveCount(player *Player) int {
+	vanguard := player.Vanguard.TopCard
+	if vanguard == nil {
+		return 0
+	}
+	count := party.StatOf(vanguard, StatDrive)
+	if count < 0 {
+		return 0
+	}
+	return count
+}
+
+// battle runs one attack of the turn player, returns false once no attack is declared.
+func (party *Party) battle(player *Player) bool {
+	opponent := party.opponent(player)
+	if opponent == nil {
+		return false
+	}
+
+	// Attack step: a standing front row unit attacks an opponent's front row unit
+	attackers := []CircleID{}
+	for _, id := range player.OccupiedCircles(FrontRow) {
+		if card := player.Circle(id).TopCard; !card.Rested && !card.Locked {
+			attackers = append(attackers, id)
+		}
+	}
+	targets := opponent.OccupiedCircles(FrontRow)
+	if len(attackers) == 0 || len(targets) == 0 {
+		return false
+	}
+
+	chosen := party.choose(player, Choice{Kind: ChoiceAttacker, Prompt: "Choose a unit to attack with", Options: circleOptions(player, attackers), Min: 0, Max: 1})
+	if len(chosen) == 0 {
+		return false
+	}
+	attackerCircle := attackers[chosen[0]]
+
+	chosen = party.choose(player, Choice{Kind: ChoiceTarget, Prompt: "Choose the unit to attack", Options: circleOptions(opponent, targets), Min: 1, Max: 1})
+	targetCircle := targets[chosen[0]]
+
+	party.Battle = &Battle{
+		Attacker:       player.Circle(attackerCircle).TopCard,
+		AttackerCircle: attackerCircle,
+		Target:         opponent.Circle(targetCircle).TopCard,
+		TargetCircle:   targetCircle,
+		Guardians:      []*Card{},
+		CannotBeHit:    map[string]bool{},
+	}
+	party.Battle.Attacker.Rested = true
+	party.logCard(LogAttack, player, party.Battle.Attacker, string(targetCircle)+": "+party.Battle.Target.Name)
+	party.boostStep(player)
+
+	fmt.Printf("Battle: %s attacks %s\n", party.Battle.Attacker.Name, party.Battle.Target.Name)
+	party.emit(TimingAttack, party.Battle.Attacker)
+	party.emit(TimingAttacked, party.Battle.Target)
+
+	party.guardStep(opponent)
+
+	if attackerCircle == CircleVanguard {
+		party.driveStep(player)
+	}
+
+	party.damageStep(player, opponent)
+	party.closeStep(opponent)
+	return true
+}
+
+// boostStep lets the unit behind the attacker boost it: it rests and its power is added to
+// the attacker's until end of the battle.
+func (party *Party) boostStep(player *Player) {
+	battle := party.Battle
+	behind, ok := BackRowOf(battle.AttackerCircle)
+	if !ok {
+		return
+	}
+	booster := player.Circle(behind).TopCard
+	if booster == nil || booster.Rested || booster.Locked || !booster.HasSkill(SkillBoost) {
+		return
+	}
+
+	chosen := party.choose(player, Choice{Kind: ChoiceBoost, Prompt: "Boost " + battle.Attacker.Name + "?", Options: circleOptions(player, []CircleID{behind}), Min: 0, Max: 1})
+	if len(chosen) == 0 {
+		return
+	}
+
+	booster.Rested = true
+	battle.Booster = booster
+	battle.BoosterCircle = behind
+	party.logCard(LogBoost, player, booster, battle.Attacker.Name)
+	fmt.Printf("Battle: %s boosts %s\n", booster.Name, battle.Attacker.Name)
+	party.emit(TimingBoosting, booster)
+	party.emit(TimingBoosted, battle.Attacker)
+}
+
+// AttackPower is the attacker's power, boost included while the booster stays on its circle.
+func (party *Party) AttackPower(player *Player) int {
+	battle := party.Battle
+	if battle == nil {
+		return 0
+	}
+	power := party.StatOf(battle.Attacker, StatPower)
+	if battle.Booster != nil && player.Circle(battle.BoosterCircle).TopCard == battle.Booster {
+		power += party.StatOf(battle.Booster, StatPower)
+	}
+	return power
+}
+
+// guardStep lets the defender call units from hand to (GC) and intercept with
+// front row grade 2 rear-guards, then activate the sentinels that were called.
+func (party *Party) guardStep(defender *Player) {
+	type guardOption struct {
+		card   *Card
+		circle CircleID
+	}
+	candidates := []guardOption{}
+	options := []string{}
+
+	for _, card := range defender.Hand {
+		if card.CardType.IsUnit() {
+			candidates = append(candidates, guardOption{card: card})
+			options = append(options, "Call: "+ToString(card))
+		}
+	}
+	for _, id := range defender.OccupiedCircles([]CircleID{CircleRear1, CircleRear2}) {
+		card := defender.Circle(id).TopCard
+		if card.Grade == 2 && !card.Locked && card.HasSkill(SkillIntercept) && card != party.Battle.Target {
+			candidates = append(candidates, guardOption{card: card, circle: id})
+			options = append(options, "Intercept "+string(id)+": "+ToString(card))
+		}
+	}
+
+	chosen := party.choose(defender, Choice{Kind: ChoiceGuard, Prompt: "Choose guardians to call", Options: options, Min: 0, Max: len(options)})
+	for _, index := range chosen {
+		candidate := candidates[index]
+		if isSentinel(candidate.card) {
+			if party.Battle.Sentinels >= SentinelsPerBattle {
+				fmt.Printf("Guard: only %d sentinel per battle, %s stays\n", SentinelsPerBattle, candidate.card.Name)
+				continue
+			}
+			party.Battle.Sentinels++
+		}
+
+		from := ZoneHand
+		if candidate.circle != "" {
+			defender.Circle(candidate.circle).TopCard = nil
+			from = ZoneField
+		} else {
+			removeFromZone(&defender.Hand, candidate.card)
+		}
+		defender.GuardZone = append(defender.GuardZone, candidate.card)
+		party.logMove(defender, candidate.card, from, ZoneGuard, candidate.circle)
+		party.Battle.Guardians = append(party.Battle.Guardians, candidate.card)
+	}
+
+	for _, guardian := range party.Battle.Guardians {
+		if isSentinel(guardian) {
+			party.activateSentinel(defender, guardian)
+		}
+	}
+}
+
+// activateSentinel resolves "COST [Discard a card from your hand], choose one of your units,
+// and that unit cannot be hit until end of that battle".
+func (party *Party) activateSentinel(defender *Player, sentinel *Card) {
+	if !party.CanPay(defender, SentinelCost) {
+		return
+	}
+
+	circles := defender.OccupiedCircles(AllCircles)
+	chosen := party.choose(defender, Choice{Kind: ChoiceSentinel, Prompt: sentinel.Name + ": pay " + SentinelCost.String() + " to choose a unit that cannot be hit", Options: circleOptions(defender, circles), Min: 0, Max: 1})
+	if len(chosen) == 0 || !party.PayCost(defender, SentinelCost) {
+		return
+	}
+
+	protected := defender.Circle(circles[chosen[0]]).TopCard
+	fmt.Printf("Guard: %s cannot be hit\n", protected.Name)
+	party.PreventHit(protected)
+}
+
+func (party *Party) driveStep(player *Player) {
+	for i := party.DriveCount(player); i > 0; i-- {
+		card := party.triggerCheck(player, TimingDriveCheck)
+		if card == nil {
+			return
+		}
+		// Over triggers are removed from the game by the trigger itself
+		if removeFromZone(&player.TriggerZone, card) {
+			player.Hand = append(player.Hand, card)
+			party.logMove(player, card, ZoneTrigger, ZoneHand, "")
+		}
+	}
+}
+
+func (party *Party) damageStep(player *Player, defender *Player) {
+	battle := party.Battle
+
+	defense := party.StatOf(battle.Target, StatPower)
+	for _, guardian := range battle.Guardians {
+		defense += party.StatOf(guardian, StatShield)
+	}
+	attack := party.AttackPower(player)
+
+	// The target may have left its circle during the battle
+	if defender.Circle(battle.TargetCircle).TopCard != battle.Target || battle.CannotBeHit[battle.Target.ID] || attack < defense {
+		fmt.Printf("Battle: no hit (%d vs %d)\n", attack, defense)
+		party.log(LogEntry{Action: LogBattle, Player: party.playerIndex(player), Values: []int{attack, defense}, Detail: "no hit"})
+		return
+	}
+
+	battle.Hit = true
+	fmt.Printf("Battle: hit (%d vs %d)\n", attack, defense)
+	party.log(LogEntry{Action: LogBattle, Player: party.playerIndex(player), Values: []int{attack, defense}, Detail: "hit"})
+	party.emit(TimingHit, battle.Attacker)
+
+	if battle.TargetCircle == CircleVanguard {
+		damage := &PendingAction{Action: ActionDamage, Player: defender, Card: battle.Attacker, Count: party.StatOf(battle.Attacker, StatCritical)}
+		if !party.replace(damage) {
+			return
+		}
+		for i := damage.Count; i > 0; i-- {
+			card := party.triggerCheck(defender, TimingDamageCheck)
+			if card == nil {
+				return
+			}
+			if removeFromZone(&defender.TriggerZone, card) {
+				defender.DamageZone = append(defender.DamageZone, card)
+				party.logMove(defender, card, ZoneTrigger, ZoneDamage, "")
+			}
+		}
+		return
+	}
+
+	party.retireUnit(defender, battle.TargetCircle)
+}
+
+// closeStep puts guardians in the drop zone and ends the battle.
+func (party *Party) closeStep(defender *Player) {
+	party.checkEffects(TimingEndOfBattle)
+	for _, guardian := range defender.GuardZone {
+		party.leaveField(guardian)
+		party.logMove(defender, guardian, ZoneGuard, ZoneDrop, "")
+	}
+	defender.DropZone = append(defender.DropZone, defender.GuardZone...)
+	defender.GuardZone = []*Card{}
+	party.expireModifiers(UntilEndOfBattle)
+	party.expireReplacements(UntilEndOfBattle)
+	party.Battle = nil
+}
+
+// triggerCheck reveals the top card of the deck in the trigger zone and resolves its trigger.
+func (party *Party) triggerCheck(player *Player, timing string) *Card {
+	if len(player.MainDeck) == 0 {
+		return nil
+	}
+	card := player.MainDeck[0]
+	player.MainDeck = player.MainDeck[1:]
+	player.TriggerZone = append(player.TriggerZone, card)
+	fmt.Printf("%s: %s\n", timing, ToString(card))
+	party.logMove(player, card, ZoneDeck, ZoneTrigger, "")
+	party.emit(timing, card)
+
+	if card.Trigger.Type != TriggerNone {
+		party.resolveTrigger(player, card)
+	}
+	return card
+}
+
+// resolveTrigger applies the trigger power to a chosen unit, then the trigger's own effect.
+func (party *Party) resolveTrigger(player *Player, trigger *Card) {
+	chooseUnit := func(prompt string) *Card {
+		circles := player.OccupiedCircles(AllCircles)
+		if len(circles) == 0 {
+			return nil
+		}
+		chosen := party.choose(player, Choice{Kind: ChoiceTrigger, Prompt: prompt, Options: circleOptions(player, circles), Min: 1, Max: 1})
+		return player.Circle(circles[chosen[0]]).TopCard
+	}
+
+	party.AddModifier(chooseUnit("Choose a unit to get the trigger power"), StatPower, trigger.Trigger.Power, UntilEndOfTurn, trigger)
+
+	switch trigger.Trigger.Type {
+	case TriggerCritical:
+		party.AddModifier(chooseUnit("Choose a unit to get Critical+1"), StatCritical, 1, UntilEndOfTurn, trigger)
+	case TriggerDraw:
+		party.Draw(player, 1)
+	case TriggerFront:
+		for _, id := range player.OccupiedCircles(FrontRow) {
+			party.AddModifier(player.Circle(id).TopCard, StatPower, TriggerPower, UntilEndOfTurn, trigger)
+		}
+	case TriggerHeal:
+		opponent := party.opponent(player)
+		if len(player.DamageZone) > 0 && opponent != nil && len(player.DamageZone) >= len(opponent.DamageZone) {
+			chosen := party.choose(player, Choice{Kind: ChoiceHeal, Prompt: "Choose a damage to heal", Options: cardOptions(player.DamageZone), Min: 1, Max: 1})
+			healed := player.DamageZone[chosen[0]]
+			removeFromZone(&player.DamageZone, healed)
+			player.DropZone = append(player.DropZone, healed)
+			party.logMove(player, healed, ZoneDamage, ZoneDrop, "")
+		}
+	case TriggerStand:
+		rested := []CircleID{}
+		for _, id := range player.OccupiedCircles(rearGuardCircles()) {
+			if player.Circle(id).TopCard.Rested {
+				rested = append(rested, id)
+			}
+		}
+		if len(rested) > 0 {
+			chosen := party.choose(player, Choice{Kind: ChoiceTrigger, Prompt: "Choose a rear-guard to stand", Options: circleOptions(player, rested), Min: 1, Max: 1})
+			card := player.Circle(rested[chosen[0]]).TopCard
+			card.Rested = false
+			party.logCard(LogStand, player, card, "")
+		}
+	case TriggerOver:
+		// Removed from the game, the card is in no zone anymore
+		removeFromZone(&player.TriggerZone, trigger)
+		party.logMove(player, trigger, ZoneTrigger, ZoneRemoved, "")
+		party.Draw(player, 1)
+	}
+}
+
+// retireUnit puts the unit of a rear-guard circle into the drop zone.
+func (party *Party) retireUnit(player *Player, id CircleID) {
+	party.Retire(player.Circle(id).TopCard)
+}
+
+// removeFromZone removes card from the zone, reporting whether it was there.
+func removeFromZone(zone *[]*Card, card *Card) bool {
+	for i, c := range *zone {
+		if c == card {
+			*zone = append((*zone)[:i], (*zone)[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
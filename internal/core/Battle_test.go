@@ -0,0 +1,31 @@
+package core
+
+import "testing"
+
+// An over trigger leaves the game: it ends in no zone and its owner draws.
+func TestOverTriggerIsRemovedFromTheGame(t *testing.T) {
+	party, _ := emptyBoard(t)
+	player := &party.Players[0]
+	over := &Card{Name: "Over", CardType: CardTypeTriggerUnit, Trigger: TriggerInfo{Type: TriggerOver, Power: OverTriggerPower}}
+	player.TriggerZone = append(player.TriggerZone, over)
+	hand := len(player.Hand)
+
+	party.resolveTrigger(player, over)
+
+	if owner := party.ownerOf(over); owner != nil {
+		zone, _, _ := owner.Locate(over)
+		t.Errorf("over trigger still in the %s zone", zone)
+	}
+	if len(player.Hand) != hand+1 {
+		t.Errorf("hand has %d cards, want %d", len(player.Hand), hand+1)
+	}
+	var moved LogEntry
+	for _, entry := range party.History {
+		if entry.Action == LogMove && entry.Card == over.ID {
+			moved = entry
+		}
+	}
+	if moved.To != ZoneRemoved {
+		t.Errorf("over trigger logged as going to %q, want %q", moved.To, ZoneRemoved)
+	}
+}
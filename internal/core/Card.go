@@ -2,6 +2,7 @@ package core
 
 import (
 	"errors"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -34,6 +35,119 @@ type RawCard struct {
 	Flavor         string `json:"flavor"`
 }
 
+// NoGrade is the grade of cards printed without one (orders, crests).
+const NoGrade = -1
+
+// Trigger power bonuses in D Format.
+const (
+	TriggerPower     = 10000
+	OverTriggerPower = 100000000
+)
+
+// CardType is the printed card type.
+type CardType string
+
+const (
+	CardTypeUnknown     CardType = ""
+	CardTypeNormalUnit  CardType = "Normal Unit"
+	CardTypeTriggerUnit CardType = "Trigger Unit"
+	CardTypeGUnit       CardType = "G Unit"
+	CardTypeNormalOrder CardType = "Normal Order"
+	CardTypeSetOrder    CardType = "Set Order"
+	CardTypeBlitzOrder  CardType = "Blitz Order"
+	CardTypeCrest       CardType = "Crest"
+)
+
+func (t CardType) IsUnit() bool {
+	return t == CardTypeNormalUnit || t == CardTypeTriggerUnit || t == CardTypeGUnit
+}
+
+func (t CardType) IsOrder() bool {
+	return t == CardTypeNormalOrder || t == CardTypeSetOrder || t == CardTypeBlitzOrder
+}
+
+type SkillType string
+
+const (
+	SkillBoost       SkillType = "Boost"
+	SkillIntercept   SkillType = "Intercept"
+	SkillDrive       SkillType = "Drive"
+	SkillTwinDrive   SkillType = "Twin Drive"
+	SkillTripleDrive SkillType = "Triple Drive"
+	// SkillSentinel marks perfect guards, limited to one per battle
+	SkillSentinel SkillType = "Sentinel"
+)
+
+// sentinelText is the ability printed on perfect guards that do not list Sentinel as a skill.
+const sentinelText = "when this unit is placed on (gc), cost [discard a card from your hand], choose one of your units, and that unit cannot be hit until end of that battle"
+
+// hasSentinelText tells whether the card's only ability is the perfect guard ability.
+func hasSentinelText(effects []CardText) bool {
+	if len(effects) != 1 {
+		return false
+	}
+	text := strings.ToLower(strings.TrimSpace(effects[0].Description))
+	text = strings.TrimPrefix(strings.TrimPrefix(text, "[auto]"), ":")
+	return strings.TrimSuffix(strings.TrimSpace(text), ".") == sentinelText
+}
+
+type GiftType string
+
+const (
+	GiftNone      GiftType = ""
+	GiftForceI    GiftType = "Force I"
+	GiftForceII   GiftType = "Force II"
+	GiftAccelI    GiftType = "Accel I"
+	GiftAccelII   GiftType = "Accel II"
+	GiftProtectI  GiftType = "Protect I"
+	GiftProtectII GiftType = "Protect II"
+)
+
+type TriggerType string
+
+const (
+	TriggerNone     TriggerType = ""
+	TriggerCritical TriggerType = "Critical"
+	TriggerDraw     TriggerType = "Draw"
+	TriggerFront    TriggerType = "Front"
+	TriggerHeal     TriggerType = "Heal"
+	TriggerStand    TriggerType = "Stand"
+	TriggerOver     TriggerType = "Over"
+)
+
+var triggerTypes = []TriggerType{TriggerCritical, TriggerDraw, TriggerFront, TriggerHeal, TriggerStand, TriggerOver}
+
+// TriggerInfo describes the trigger icon of a trigger unit, Type is TriggerNone otherwise.
+type TriggerInfo struct {
+	Type  TriggerType
+	Power int
+}
+
+// CardFieldError is a RawCard field that could not be converted.
+type CardFieldError struct {
+	Field  string
+	Value  string
+	Reason string
+}
+
+func (e CardFieldError) String() string {
+	return e.Field + " " + strconv.Quote(e.Value) + ": " + e.Reason
+}
+
+// CardParseError lists every field problem found by ToCard.
+type CardParseError struct {
+	CardNumberFull string
+	Fields         []CardFieldError
+}
+
+func (e *CardParseError) Error() string {
+	fields := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		fields[i] = field.String()
+	}
+	return e.CardNumberFull + ": " + strings.Join(fields, ", ")
+}
+
 type Boon struct {
 	// TODO: Define Boon struct
 }
@@ -51,13 +165,23 @@ type Card struct {
 	Shield         int
 	Clan           []string
 	Skill          []string
+	Trigger        TriggerInfo
 	Gift           string
+	CardType       CardType
+	Skills         []SkillType
+	GiftType       GiftType
 	Rarity         string
 	Illustrator    []string
 	Effect         []CardText
+	Abilities      []Ability
 	Flavor         string
 	Boons          []Boon
 	Locked         bool
+	Rested         bool
+	// FaceDown is set on damage paid by Counter Blast
+	FaceDown bool
+	// Instance changes each time the card leaves the field, see Party.leaveField
+	Instance int
 }
 
 func ToString(card *Card) string {
@@ -69,11 +193,44 @@ func ToString(card *Card) string {
 	if card.Locked {
 		locked = " [LOCKED]"
 	}
+	if card.Rested {
+		locked += " [REST]"
+	}
 
 	return locked + "[" + card.ID + "] G" + strconv.Itoa(card.Grade) + " - " + card.Name + " => " + card.CardNumberFull + " ATK : " + strconv.Itoa(card.Power) + " DEF : " + strconv.Itoa(card.Shield) + " CRIT : " + strconv.Itoa(card.Critical)
 }
 
+// Clone returns a copy of the card with its own ID and a fresh state.
+func (card *Card) Clone() *Card {
+	if card == nil {
+		return nil
+	}
+	clone := *card
+	clone.ID = uuid.New().String()
+	clone.Locked = false
+	clone.Rested = false
+	clone.FaceDown = false
+	clone.Instance = 0
+	clone.Boons = []Boon{}
+	// Slices are copied so no state is shared between instances
+	clone.Type = slices.Clone(card.Type)
+	clone.Nation = slices.Clone(card.Nation)
+	clone.Race = slices.Clone(card.Race)
+	clone.Clan = slices.Clone(card.Clan)
+	clone.Skill = slices.Clone(card.Skill)
+	clone.Skills = slices.Clone(card.Skills)
+	clone.Illustrator = slices.Clone(card.Illustrator)
+	clone.Effect = slices.Clone(card.Effect)
+	// Scripted abilities are built again, each instance gets its own
+	if !applyCardScript(&clone) {
+		clone.Abilities = slices.Clone(card.Abilities)
+	}
+	return &clone
+}
 
+// ToCard converts the database strings into a Card.
+// When some fields are malformed, the card is still returned (those fields left at 0)
+// together with a *CardParseError listing them.
 func (rc *RawCard) ToCard() (*Card, error) {
 	if rc == nil {
 		return nil, errors.New("RawCard is nil")
@@ -91,16 +248,25 @@ func (rc *RawCard) ToCard() (*Card, error) {
 		})
 	}
 
-	grade := -1
-	if rc.Grade != "" {
-		grade, _ = strconv.Atoi(strings.Replace(rc.Grade, "Grade ", "", -1))
+	parseErr := &CardParseError{CardNumberFull: rc.CardNumberFull}
+
+	cardType := parseCardType(rc.Type)
+
+	grade, hasGrade := parseCardStat(parseErr, "grade", rc.Grade, "Grade")
+	if !hasGrade {
+		grade = NoGrade
+		if cardType.IsUnit() {
+			parseErr.Fields = append(parseErr.Fields, CardFieldError{Field: "grade", Value: rc.Grade, Reason: "units must have a grade"})
+		}
 	}
 
-	power, _ := strconv.Atoi(strings.Replace(rc.Power, "Power ", "", -1))
-	critical, _ := strconv.Atoi(strings.Replace(rc.Critical, "Critical ", "", -1))
-	shield, _ := strconv.Atoi(strings.Replace(rc.Shield, "Shield ", "", -1))
+	power, _ := parseCardStat(parseErr, "power", rc.Power, "Power")
+	critical, _ := parseCardStat(parseErr, "critical", rc.Critical, "Critical")
+	shield, _ := parseCardStat(parseErr, "shield", rc.Shield, "Shield")
+
+	skills := splitCardList(rc.Skill)
 
-	return &Card{
+	card := &Card{
 		ID:             uuid.New().String(),
 		CardNumberFull: rc.CardNumberFull,
 		Name:           rc.Name,
@@ -112,12 +278,145 @@ func (rc *RawCard) ToCard() (*Card, error) {
 		Critical:       critical,
 		Shield:         shield,
 		Clan:           strings.Split(rc.Clan, "/"),
-		Skill:          []string{rc.Skill},
+		Skill:          skills,
+		Trigger:        parseTrigger(rc.Type, rc.Skill, rc.Gift),
 		Gift:           rc.Gift,
+		CardType:       cardType,
+		Skills:         parseSkills(skills),
+		GiftType:       parseGift(rc.Gift),
 		Rarity:         rc.Rarity,
 		Illustrator:    strings.Split(rc.Illustrator, "/"),
 		Effect:         ParsedEffects,
 		Flavor:         rc.Flavor,
 		Boons:          []Boon{},
-	}, nil
+	}
+	if !card.HasSkill(SkillSentinel) && hasSentinelText(ParsedEffects) {
+		card.Skills = append(card.Skills, SkillSentinel)
+	}
+
+	applyCardScript(card)
+
+	if len(parseErr.Fields) > 0 {
+		return card, parseErr
+	}
+	return card, nil
+}
+
+// parseCardStat reads values like "Power 13000", "13000+" or "-".
+// Empty and "-" values are reported as absent, not as errors.
+func parseCardStat(parseErr *CardParseError, field string, value string, prefix string) (int, bool) {
+	text := strings.TrimSpace(value)
+	text = strings.TrimSpace(strings.TrimPrefix(text, prefix))
+	if text == "" || text == "-" {
+		return 0, false
+	}
+
+	text = strings.TrimSuffix(strings.ReplaceAll(text, ",", ""), "+")
+	number, err := strconv.Atoi(strings.TrimSpace(text))
+	if err != nil {
+		parseErr.Fields = append(parseErr.Fields, CardFieldError{Field: field, Value: value, Reason: "not a number"})
+		return 0, false
+	}
+	return number, true
+}
+
+// splitCardList splits "Boost/Intercept" style values, dropping empty and "-" entries.
+func splitCardList(value string) []string {
+	items := []string{}
+	for _, item := range strings.FieldsFunc(value, func(r rune) bool { return r == '/' || r == ',' || r == '\n' }) {
+		item = strings.TrimSpace(item)
+		if item != "" && item != "-" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// HasSkill reports whether the card has the given printed skill.
+func (card *Card) HasSkill(skill SkillType) bool {
+	for _, s := range card.Skills {
+		if s == skill {
+			return true
+		}
+	}
+	return false
+}
+
+// parseCardType reads the type field, e.g. "Normal Unit" or "Trigger Unit/..." .
+func parseCardType(value string) CardType {
+	text := strings.ToLower(value)
+	// Most specific names first: "set order" and "g unit" also contain "order" and "unit"
+	checks := []struct {
+		text     string
+		cardType CardType
+	}{
+		{"trigger unit", CardTypeTriggerUnit},
+		{"g unit", CardTypeGUnit},
+		{"normal unit", CardTypeNormalUnit},
+		{"set order", CardTypeSetOrder},
+		{"blitz order", CardTypeBlitzOrder},
+		{"order", CardTypeNormalOrder},
+		{"crest", CardTypeCrest},
+		{"unit", CardTypeNormalUnit},
+	}
+	for _, check := range checks {
+		if strings.Contains(text, check.text) {
+			return check.cardType
+		}
+	}
+	return CardTypeUnknown
+}
+
+func parseSkills(skills []string) []SkillType {
+	result := []SkillType{}
+	for _, skill := range skills {
+		switch text := strings.ToLower(skill); {
+		case strings.Contains(text, "triple drive"):
+			result = append(result, SkillTripleDrive)
+		case strings.Contains(text, "twin drive"):
+			result = append(result, SkillTwinDrive)
+		case strings.Contains(text, "drive"):
+			result = append(result, SkillDrive)
+		case strings.Contains(text, "boost"):
+			result = append(result, SkillBoost)
+		case strings.Contains(text, "intercept"):
+			result = append(result, SkillIntercept)
+		case strings.Contains(text, "sentinel"):
+			result = append(result, SkillSentinel)
+		}
+	}
+	return result
+}
+
+func parseGift(value string) GiftType {
+	text := strings.ToLower(strings.TrimSpace(value))
+	for _, gift := range []GiftType{GiftForceII, GiftAccelII, GiftProtectII, GiftForceI, GiftAccelI, GiftProtectI} {
+		if strings.Contains(text, strings.ToLower(string(gift))) {
+			return gift
+		}
+	}
+	// "Force" without a level is the first one
+	for _, gift := range []GiftType{GiftForceI, GiftAccelI, GiftProtectI} {
+		if strings.Contains(text, strings.ToLower(strings.TrimSuffix(string(gift), " I"))) {
+			return gift
+		}
+	}
+	return GiftNone
+}
+
+// parseTrigger looks for "<Type> Trigger" in the type, skill and gift texts.
+func parseTrigger(texts ...string) TriggerInfo {
+	for _, text := range texts {
+		text = strings.ToLower(text)
+		for _, trigger := range triggerTypes {
+			if strings.Contains(text, strings.ToLower(string(trigger))+" trigger") {
+				power := TriggerPower
+				if trigger == TriggerOver {
+					power = OverTriggerPower
+				}
+				return TriggerInfo{Type: trigger, Power: power}
+			}
+		}
+	}
+	return TriggerInfo{}
 }
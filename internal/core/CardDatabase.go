@@ -0,0 +1,156 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// DefaultCardDatabasePath is used when no database location is configured.
+const DefaultCardDatabasePath = "vg_parsed_cards.json"
+
+// CardDatabaseEnv lists the database files to load, separated by the OS path list separator.
+const CardDatabaseEnv = "VG_CARD_DB"
+
+// CardConflict records a card number defined twice with different data.
+// The later source wins, the conflict is kept so it can be reported.
+type CardConflict struct {
+	CardNumberFull string
+	Previous       string
+	Current        string
+}
+
+func (c CardConflict) String() string {
+	return c.CardNumberFull + ": " + c.Previous + " overridden by " + c.Current
+}
+
+// CardDatabase merges one or more card JSON files, indexed by card_number_full.
+type CardDatabase struct {
+	Sources   []string
+	Conflicts []CardConflict
+	cards     map[string]RawCard
+	origins   map[string]string
+}
+
+func NewCardDatabase() *CardDatabase {
+	return &CardDatabase{
+		Sources:   []string{},
+		Conflicts: []CardConflict{},
+		cards:     make(map[string]RawCard),
+		origins:   make(map[string]string),
+	}
+}
+
+// LoadCardDatabase loads the given files in order, later files overriding earlier ones
+// (base sets, then custom/proxy cards, then local overrides).
+func LoadCardDatabase(paths ...string) (*CardDatabase, error) {
+	if len(paths) == 0 {
+		return nil, errors.New("no card database file given")
+	}
+
+	db := NewCardDatabase()
+	for _, path := range paths {
+		if err := db.LoadFile(path); err != nil {
+			return nil, err
+		}
+	}
+	return db, nil
+}
+
+// LoadFile decodes a JSON array of RawCard and merges it into the database.
+func (db *CardDatabase) LoadFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var cards []RawCard
+	if err := json.NewDecoder(file).Decode(&cards); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	db.Add(path, cards)
+	return nil
+}
+
+// Add merges cards coming from the named source.
+func (db *CardDatabase) Add(source string, cards []RawCard) {
+	db.Sources = append(db.Sources, source)
+
+	for _, card := range cards {
+		if card.CardNumberFull == "" {
+			continue
+		}
+		if previous, exists := db.cards[card.CardNumberFull]; exists && previous != card {
+			db.Conflicts = append(db.Conflicts, CardConflict{
+				CardNumberFull: card.CardNumberFull,
+				Previous:       db.origins[card.CardNumberFull],
+				Current:        source,
+			})
+		}
+		db.cards[card.CardNumberFull] = card
+		db.origins[card.CardNumberFull] = source
+	}
+}
+
+// Find returns a copy of the card with the given number, or nil.
+func (db *CardDatabase) Find(cardNumber string) *RawCard {
+	card, exists := db.cards[strings.TrimSpace(cardNumber)]
+	if !exists {
+		return nil
+	}
+	return &card
+}
+
+func (db *CardDatabase) Len() int {
+	return len(db.cards)
+}
+
+var (
+	defaultDatabase     *CardDatabase
+	defaultDatabaseLock sync.Mutex
+)
+
+// CardDatabasePaths returns the configured database files, from VG_CARD_DB or the default path.
+func CardDatabasePaths() []string {
+	paths := []string{}
+	for _, path := range filepath.SplitList(os.Getenv(CardDatabaseEnv)) {
+		if path = strings.TrimSpace(path); path != "" {
+			paths = append(paths, path)
+		}
+	}
+	if len(paths) == 0 {
+		paths = append(paths, DefaultCardDatabasePath)
+	}
+	return paths
+}
+
+// SetCardDatabase replaces the database used by ParseDeckFile.
+func SetCardDatabase(db *CardDatabase) {
+	defaultDatabaseLock.Lock()
+	defer defaultDatabaseLock.Unlock()
+	defaultDatabase = db
+}
+
+// DefaultCardDatabase returns the shared database, loading CardDatabasePaths on first use.
+func DefaultCardDatabase() (*CardDatabase, error) {
+	defaultDatabaseLock.Lock()
+	defer defaultDatabaseLock.Unlock()
+
+	if defaultDatabase == nil {
+		db, err := LoadCardDatabase(CardDatabasePaths()...)
+		if err != nil {
+			return nil, err
+		}
+		for _, conflict := range db.Conflicts {
+			println("Card database conflict: " + conflict.String())
+		}
+		defaultDatabase = db
+	}
+	return defaultDatabase, nil
+}
@@ -0,0 +1,169 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// CardScript is a hand-written implementation of a card's abilities, used instead of the
+// parsed CardText for cards the text cannot describe. Key is a card_number_full ("DZ-TD01/005EN")
+// or a card name covering every printing, a card number taking precedence over a name.
+// Abilities is called for each card instance so that no state is shared between copies.
+type CardScript struct {
+	Key string
+	// Zone is where the card's abilities work from, the field by default, ZoneCrest for crests
+	Zone      Zone
+	Abilities func() []Ability
+}
+
+var (
+	cardScripts     = map[string]CardScript{}
+	cardScriptsLock sync.RWMutex
+)
+
+// RegisterCardScript registers a script, replacing any script with the same key.
+func RegisterCardScript(script CardScript) {
+	script.Key = strings.TrimSpace(script.Key)
+	cardScriptsLock.Lock()
+	defer cardScriptsLock.Unlock()
+	if _, exists := cardScripts[script.Key]; exists {
+		println("Card script replaced: " + script.Key)
+	}
+	cardScripts[script.Key] = script
+}
+
+// CardScriptFor returns the script of a card, looked up by number then by name.
+func CardScriptFor(card *Card) (CardScript, bool) {
+	if card == nil {
+		return CardScript{}, false
+	}
+	cardScriptsLock.RLock()
+	defer cardScriptsLock.RUnlock()
+	if script, ok := cardScripts[card.CardNumberFull]; ok {
+		return script, true
+	}
+	script, ok := cardScripts[card.Name]
+	return script, ok
+}
+
+// ScriptedCards lists the keys of every registered script, sorted.
+func ScriptedCards() []string {
+	cardScriptsLock.RLock()
+	defer cardScriptsLock.RUnlock()
+	keys := make([]string, 0, len(cardScripts))
+	for key := range cardScripts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// applyCardScript replaces the card's abilities with its script, if it has one.
+func applyCardScript(card *Card) bool {
+	script, ok := CardScriptFor(card)
+	if !ok {
+		return false
+	}
+	card.Abilities = script.Abilities()
+	return true
+}
+
+// VerifyCardScripts loads every scripted card into a sample board state and runs each of
+// its abilities once, reporting abilities that panic or cannot be activated.
+func VerifyCardScripts() []error {
+	errs := []error{}
+	for _, key := range ScriptedCards() {
+		cardScriptsLock.RLock()
+		script := cardScripts[key]
+		cardScriptsLock.RUnlock()
+
+		for i, ability := range script.Abilities() {
+			if err := verifyAbility(key, script, i); err != nil {
+				errs = append(errs, fmt.Errorf("%s: ability #%d (%s %s): %w", key, i+1, ability.Kind, ability.Description, err))
+			}
+		}
+	}
+	return errs
+}
+
+func verifyAbility(key string, script CardScript, index int) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	party, card := sampleBoard(key, script)
+	player := &party.Players[0]
+	ability := card.Abilities[index]
+
+	switch ability.Kind {
+	case AbilityACT:
+		return party.ActivateAbility(player, card, index)
+	case AbilityAUTO:
+		if ability.Timing == "" {
+			return errors.New("AUTO ability without timing")
+		}
+		if ability.Effect == nil {
+			return errors.New("AUTO ability without effect")
+		}
+		party.emit(ability.Timing, card)
+	}
+	return nil
+}
+
+// sampleBoard builds a two player party in the middle of a turn: vanguards, rear-guards,
+// hand, soul, damage and full energy, with the scripted card in its natural place.
+func sampleBoard(key string, script CardScript) (*Party, *Card) {
+	unit := func(name string, grade int) *Card {
+		card := &Card{Name: name, Grade: grade, Power: 5000 + 5000*grade, Critical: 1, Shield: 5000, CardType: CardTypeNormalUnit, Skills: []SkillType{SkillBoost}}
+		return card.Clone()
+	}
+	zone := func(name string, count int) []*Card {
+		cards := make([]*Card, count)
+		for i := range cards {
+			cards[i] = unit(name, i%4)
+		}
+		return cards
+	}
+
+	players := make([]Player, 2)
+	for i := range players {
+		players[i] = DeckToPlayer(Deck{MainDeck: zone("Sample Deck Card", 20)})
+		player := &players[i]
+		player.Hand = zone("Sample Hand Card", 5)
+		player.DamageZone = zone("Sample Damage", 3)
+		player.DropZone = zone("Sample Drop Card", 3)
+		player.Energy = MaxEnergy
+		player.Vanguard = Circle{TopCard: unit("Sample Vanguard", 3), Soul: zone("Sample Soul", 3)}
+		player.Rear1.TopCard = unit("Sample Rear-guard", 2)
+		player.Rear4.TopCard = unit("Sample Booster", 1)
+	}
+
+	party := InitParty([]*Deck{})
+	party.Players = players
+	party.Turn = 3
+	party.CurrentPhase = PhaseMain
+	party.rand = rand.New(rand.NewSource(1))
+
+	card := unit(key, 2)
+	card.CardNumberFull = key
+	card.Name = key
+	card.Abilities = script.Abilities()
+	player := &party.Players[0]
+	switch script.Zone {
+	case "", ZoneField:
+		player.Rear2.TopCard = card
+	case ZoneCrest:
+		card.CardType = CardTypeCrest
+		player.CrestZone = append(player.CrestZone, card)
+	default:
+		zone := player.zone(script.Zone)
+		*zone = append(*zone, card)
+	}
+	return party, card
+}
@@ -0,0 +1,30 @@
+package core
+
+import "testing"
+
+// Every registered script runs on the sample board, as with -verify-scripts.
+func TestScriptedCardsRunOnSampleBoard(t *testing.T) {
+	keys := ScriptedCards()
+	if len(keys) == 0 {
+		t.Fatal("no scripted card registered")
+	}
+	for _, key := range keys {
+		script, ok := CardScriptFor(&Card{Name: key, CardNumberFull: key})
+		if !ok {
+			t.Errorf("%s: listed but not found", key)
+			continue
+		}
+		for i, ability := range script.Abilities() {
+			if err := verifyAbility(key, script, i); err != nil {
+				t.Errorf("%s: ability #%d (%s %s): %v", key, i+1, ability.Kind, ability.Description, err)
+			}
+		}
+	}
+}
+
+// emptyBoard is the sample board with a card without ability on R2, for tests that only need
+// a game in progress.
+func emptyBoard(t *testing.T) (*Party, *Card) {
+	t.Helper()
+	return sampleBoard("Test Card", CardScript{Key: "Test Card", Abilities: func() []Ability { return nil }})
+}
@@ -0,0 +1,104 @@
+package core
+
+import "fmt"
+
+// ChoiceKind tells the client what a Choice is about.
+type ChoiceKind string
+
+const (
+	ChoiceAttacker ChoiceKind = "attacker"
+	ChoiceTarget   ChoiceKind = "target"
+	ChoiceBoost    ChoiceKind = "boost"
+	ChoiceGuard    ChoiceKind = "guard"
+	ChoiceTrigger  ChoiceKind = "trigger"
+	ChoiceHeal     ChoiceKind = "heal"
+	ChoiceCost     ChoiceKind = "cost"
+	ChoiceSentinel ChoiceKind = "sentinel"
+	ChoiceDiscard  ChoiceKind = "discard"
+	ChoiceCard     ChoiceKind = "card"
+	ChoiceCircle   ChoiceKind = "circle"
+	ChoiceOptional ChoiceKind = "optional"
+	ChoiceOrder    ChoiceKind = "order"
+	ChoiceRide     ChoiceKind = "ride"
+	// ChoiceMulligan and ChoiceTurnOrder are asked outside of choose, they are only recorded
+	ChoiceMulligan  ChoiceKind = "mulligan"
+	ChoiceTurnOrder ChoiceKind = "turn order"
+)
+
+// Choice is a decision a player has to make: pick between Min and Max of the Options.
+type Choice struct {
+	Kind    ChoiceKind `json:"kind"`
+	Prompt  string     `json:"prompt"`
+	Options []string   `json:"options"`
+	Min     int        `json:"min"`
+	Max     int        `json:"max"`
+}
+
+// choose asks the player through party.Decide and returns the selected option indices.
+// Without a Decide callback, or on an invalid answer, the first Min options are taken.
+func (party *Party) choose(player *Player, choice Choice) []int {
+	if choice.Max > len(choice.Options) {
+		choice.Max = len(choice.Options)
+	}
+	if choice.Min > choice.Max {
+		choice.Min = choice.Max
+	}
+
+	answer := party.decide(player, choice)
+	party.recordDecision(party.playerIndex(player), choice.Kind, answer)
+	party.log(LogEntry{Action: LogChoice, Player: party.playerIndex(player), Choice: choice.Kind, Values: answer, Detail: choice.Prompt, Hidden: true})
+	return answer
+}
+
+func (party *Party) decide(player *Player, choice Choice) []int {
+	defaultAnswer := make([]int, choice.Min)
+	for i := range defaultAnswer {
+		defaultAnswer[i] = i
+	}
+	if choice.Max == 0 || party.Decide == nil {
+		return defaultAnswer
+	}
+	if choice.Min == choice.Max && choice.Min == len(choice.Options) {
+		// Nothing to decide, everything has to be taken
+		return defaultAnswer
+	}
+
+	answer := party.Decide(party.playerIndex(player), choice)
+	if !validAnswer(choice, answer) {
+		fmt.Printf("Invalid answer %v to %q, using default\n", answer, choice.Prompt)
+		return defaultAnswer
+	}
+	return answer
+}
+
+func validAnswer(choice Choice, answer []int) bool {
+	if len(answer) < choice.Min || len(answer) > choice.Max {
+		return false
+	}
+	seen := map[int]bool{}
+	for _, index := range answer {
+		if index < 0 || index >= len(choice.Options) || seen[index] {
+			return false
+		}
+		seen[index] = true
+	}
+	return true
+}
+
+// cardOptions labels cards for a Choice.
+func cardOptions(cards []*Card) []string {
+	options := make([]string, len(cards))
+	for i, card := range cards {
+		options[i] = ToString(card)
+	}
+	return options
+}
+
+// circleOptions labels circles for a Choice, e.g. "R1: [id] G2 - Name ...".
+func circleOptions(player *Player, circles []CircleID) []string {
+	options := make([]string, len(circles))
+	for i, id := range circles {
+		options[i] = string(id) + ": " + ToString(player.Circle(id).TopCard)
+	}
+	return options
+}
@@ -51,12 +51,208 @@ func HasGradeGreaterOrEqual(grade int) Condition {
 	}
 }
 
-// HasUnitInVanguard checks if the player has a unit in Vanguard circle.
-func HasUnitInVanguard(paramName interface{}) Condition {
-	// Example of a condition that might take specific params,
-	// though usually we check the player's board state.
-	// paramName is just a placeholder here if we wanted to check for a specific Name.
+// HasUnitInVanguard checks the player has a vanguard whose name contains the given text,
+// any vanguard when the name is empty.
+func HasUnitInVanguard(name string) Condition {
 	return func(party *Party, player *Player, source *Card) bool {
-		return player.Vanguard.TopCard != nil
+		if player == nil || player.Vanguard.TopCard == nil {
+			return false
+		}
+		return name == "" || NameContains(name)(player.Vanguard.TopCard)
+	}
+}
+
+// Combinators
+
+// And is met when every condition is met, an empty And is always met.
+func And(conditions ...Condition) Condition {
+	return func(party *Party, player *Player, source *Card) bool {
+		for _, condition := range conditions {
+			if condition != nil && !condition(party, player, source) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Or is met when at least one condition is met.
+func Or(conditions ...Condition) Condition {
+	return func(party *Party, player *Player, source *Card) bool {
+		for _, condition := range conditions {
+			if condition != nil && condition(party, player, source) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func Not(condition Condition) Condition {
+	return func(party *Party, player *Player, source *Card) bool {
+		return !condition(party, player, source)
+	}
+}
+
+// Opponent checks a condition from the opponent's side ("if your opponent has four or more damage").
+func Opponent(condition Condition) Condition {
+	return func(party *Party, player *Player, source *Card) bool {
+		opponent := party.opponent(player)
+		return opponent != nil && condition(party, opponent, nil)
+	}
+}
+
+// Zone counts
+
+// ZoneCountAtLeast checks the player has 'count' or more cards in a zone.
+func ZoneCountAtLeast(zone Zone, count int) Condition {
+	return func(party *Party, player *Player, source *Card) bool {
+		if player == nil {
+			return false
+		}
+		cards := player.zone(zone)
+		return cards != nil && len(*cards) >= count
+	}
+}
+
+func DamageAtLeast(count int) Condition {
+	return ZoneCountAtLeast(ZoneDamage, count)
+}
+
+func SoulAtLeast(count int) Condition {
+	return ZoneCountAtLeast(ZoneSoul, count)
+}
+
+func HandAtLeast(count int) Condition {
+	return ZoneCountAtLeast(ZoneHand, count)
+}
+
+func DropAtLeast(count int) Condition {
+	return ZoneCountAtLeast(ZoneDrop, count)
+}
+
+// FaceUpDamageAtLeast checks there is enough face up damage to Counter Blast 'count'.
+func FaceUpDamageAtLeast(count int) Condition {
+	return func(party *Party, player *Player, source *Card) bool {
+		return player != nil && len(faceUpDamage(player)) >= count
+	}
+}
+
+// Units
+
+// VanguardMatches checks the player's vanguard matches the filter.
+func VanguardMatches(filter CardFilter) Condition {
+	return func(party *Party, player *Player, source *Card) bool {
+		return player != nil && player.Vanguard.TopCard != nil && filter(player.Vanguard.TopCard)
+	}
+}
+
+// VanguardGradeAtLeast checks the grade of the player's vanguard ("if your vanguard is grade 3 or greater").
+func VanguardGradeAtLeast(grade int) Condition {
+	return VanguardMatches(GradeOrGreater(grade))
+}
+
+func VanguardHasClan(clan string) Condition {
+	return VanguardMatches(HasClan(clan))
+}
+
+// RearGuardsAtLeast checks the player has 'count' or more rear-guards matching the filter.
+func RearGuardsAtLeast(filter CardFilter, count int) Condition {
+	return func(party *Party, player *Player, source *Card) bool {
+		return player != nil && len(YourRearGuards(0).Where(filter).Candidates(party, player)) >= count
+	}
+}
+
+// HasRearGuardNamed checks the player has a rear-guard whose name contains the text.
+func HasRearGuardNamed(name string) Condition {
+	return RearGuardsAtLeast(NameContains(name), 1)
+}
+
+func HasRearGuardWithClan(clan string) Condition {
+	return RearGuardsAtLeast(HasClan(clan), 1)
+}
+
+// This unit
+
+// OnVanguardCircle is "if this unit is on (VC)".
+func OnVanguardCircle() Condition {
+	return func(party *Party, player *Player, source *Card) bool {
+		return player != nil && source != nil && player.Vanguard.TopCard == source
+	}
+}
+
+// OnRearGuardCircle is "if this unit is on (RC)".
+func OnRearGuardCircle() Condition {
+	return func(party *Party, player *Player, source *Card) bool {
+		if player == nil {
+			return false
+		}
+		id, ok := player.CircleOf(source)
+		return ok && id != CircleVanguard
+	}
+}
+
+// SourceMatches checks the card whose ability resolves matches the filter.
+func SourceMatches(filter CardFilter) Condition {
+	return func(party *Party, player *Player, source *Card) bool {
+		return source != nil && filter(source)
+	}
+}
+
+// Battle context
+
+// InBattle is met during a battle.
+func InBattle() Condition {
+	return func(party *Party, player *Player, source *Card) bool {
+		return party.Battle != nil
+	}
+}
+
+// IsAttacking is met while this unit is the attacker.
+func IsAttacking() Condition {
+	return func(party *Party, player *Player, source *Card) bool {
+		return party.Battle != nil && source != nil && party.Battle.Attacker == source
+	}
+}
+
+// IsAttacked is met while this unit is attacked.
+func IsAttacked() Condition {
+	return func(party *Party, player *Player, source *Card) bool {
+		return party.Battle != nil && source != nil && party.Battle.Target == source
+	}
+}
+
+// IsBoosted is met while this unit attacks with a boost.
+func IsBoosted() Condition {
+	return func(party *Party, player *Player, source *Card) bool {
+		return IsAttacking()(party, player, source) && party.Battle.Booster != nil
+	}
+}
+
+// IsBoosting is met while this unit boosts the attacker.
+func IsBoosting() Condition {
+	return func(party *Party, player *Player, source *Card) bool {
+		return party.Battle != nil && source != nil && party.Battle.Booster == source
+	}
+}
+
+// AttackerMatches checks the attacking unit matches the filter ("when attacked by a grade 3 or greater").
+func AttackerMatches(filter CardFilter) Condition {
+	return func(party *Party, player *Player, source *Card) bool {
+		return party.Battle != nil && filter(party.Battle.Attacker)
+	}
+}
+
+// AttackedByVanguard is met when the attacking unit is on (VC).
+func AttackedByVanguard() Condition {
+	return func(party *Party, player *Player, source *Card) bool {
+		return party.Battle != nil && party.Battle.AttackerCircle == CircleVanguard
+	}
+}
+
+// IsHit is met once the attack has hit.
+func IsHit() Condition {
+	return func(party *Party, player *Player, source *Card) bool {
+		return party.Battle != nil && party.Battle.Hit
 	}
 }
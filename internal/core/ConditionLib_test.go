@@ -0,0 +1,195 @@
+package core
+
+import "testing"
+
+func testUnit(name string, grade int, clan string) *Card {
+	card := &Card{Name: name, Grade: grade, Power: 5000 + 5000*grade, Critical: 1, CardType: CardTypeNormalUnit, Clan: []string{clan}, Skills: []SkillType{SkillBoost}}
+	return card.Clone()
+}
+
+func testCards(count int) []*Card {
+	cards := make([]*Card, count)
+	for i := range cards {
+		cards[i] = testUnit("Filler", 0, "")
+	}
+	return cards
+}
+
+// testParty builds a board during turn 1 (player 0's turn):
+// player 0 has a grade 3 Royal Paladin vanguard, two rear-guards, 3 damage (1 face down),
+// 2 soul and 4 cards in hand; player 1 has a grade 2 Kagero vanguard, a booster behind it,
+// 5 damage, no soul and 1 card in hand.
+func testParty() *Party {
+	party := InitParty([]*Deck{})
+	party.Players = make([]Player, 2)
+	party.Turn = 1
+
+	first := &party.Players[0]
+	first.MainDeck = testCards(10)
+	first.Vanguard = Circle{TopCard: testUnit("Blaster Blade", 3, "Royal Paladin"), Soul: testCards(2)}
+	first.Rear1.TopCard = testUnit("Wingal", 1, "Royal Paladin")
+	first.Rear2.TopCard = testUnit("Gancelot", 2, "Gold Paladin")
+	first.DamageZone = testCards(3)
+	first.DamageZone[0].FaceDown = true
+	first.Hand = testCards(4)
+
+	second := &party.Players[1]
+	second.MainDeck = testCards(10)
+	second.Vanguard = Circle{TopCard: testUnit("Dragonic Overlord", 2, "Kagero")}
+	second.Rear4.TopCard = testUnit("Embodiment of Spear", 1, "Kagero")
+	second.DamageZone = testCards(5)
+	second.Hand = testCards(1)
+	return party
+}
+
+type conditionCase struct {
+	name      string
+	condition Condition
+	player    int
+	source    func(party *Party) *Card
+	turn      int
+	want      bool
+}
+
+func runConditionCases(t *testing.T, party *Party, cases []conditionCase) {
+	t.Helper()
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			party.Turn = 1
+			if c.turn != 0 {
+				party.Turn = c.turn
+			}
+			var source *Card
+			if c.source != nil {
+				source = c.source(party)
+			}
+			if got := c.condition(party, &party.Players[c.player], source); got != c.want {
+				t.Errorf("got %t, want %t", got, c.want)
+			}
+		})
+	}
+}
+
+func always(met bool) Condition {
+	return func(party *Party, player *Player, source *Card) bool { return met }
+}
+
+func TestConditionCombinators(t *testing.T) {
+	runConditionCases(t, testParty(), []conditionCase{
+		{name: "empty And is met", condition: And(), want: true},
+		{name: "And all met", condition: And(always(true), always(true)), want: true},
+		{name: "And one unmet", condition: And(always(true), always(false)), want: false},
+		{name: "And skips nil", condition: And(nil, always(true)), want: true},
+		{name: "empty Or is unmet", condition: Or(), want: false},
+		{name: "Or one met", condition: Or(always(false), always(true)), want: true},
+		{name: "Or none met", condition: Or(always(false), always(false)), want: false},
+		{name: "Not met", condition: Not(always(false)), want: true},
+		{name: "Not unmet", condition: Not(always(true)), want: false},
+		{name: "Opponent damage 5", condition: Opponent(DamageAtLeast(5)), want: true},
+		{name: "Opponent damage 6", condition: Opponent(DamageAtLeast(6)), want: false},
+		{name: "Opponent from player 1", condition: Opponent(DamageAtLeast(3)), player: 1, want: true},
+		{name: "Opponent of Opponent is you", condition: Opponent(Opponent(HandAtLeast(4))), want: true},
+		{name: "nested", condition: And(Or(HandAtLeast(10), SoulAtLeast(2)), Not(DamageAtLeast(4))), want: true},
+	})
+}
+
+func TestZoneCountConditions(t *testing.T) {
+	runConditionCases(t, testParty(), []conditionCase{
+		{name: "damage exactly N", condition: DamageAtLeast(3), want: true},
+		{name: "damage N+1", condition: DamageAtLeast(4), want: false},
+		{name: "damage N-1", condition: DamageAtLeast(2), want: true},
+		{name: "soul exactly N", condition: SoulAtLeast(2), want: true},
+		{name: "soul N+1", condition: SoulAtLeast(3), want: false},
+		{name: "hand exactly N", condition: HandAtLeast(4), want: true},
+		{name: "hand N+1", condition: HandAtLeast(5), want: false},
+		{name: "empty drop, zero needed", condition: DropAtLeast(0), want: true},
+		{name: "empty drop", condition: DropAtLeast(1), want: false},
+		{name: "face up damage exactly N", condition: FaceUpDamageAtLeast(2), want: true},
+		{name: "face up damage N+1", condition: FaceUpDamageAtLeast(3), want: false},
+		{name: "deck exactly N", condition: ZoneCountAtLeast(ZoneDeck, 10), want: true},
+		{name: "unknown zone", condition: ZoneCountAtLeast(Zone("nowhere"), 0), want: false},
+		{name: "player 1 soul", condition: SoulAtLeast(1), player: 1, want: false},
+		{name: "on opponent's turn", condition: DamageAtLeast(3), turn: 2, want: true},
+		{name: "opponent's hand on their turn", condition: Opponent(HandAtLeast(1)), turn: 2, want: true},
+	})
+}
+
+func TestUnitConditions(t *testing.T) {
+	party := testParty()
+	vanguard := func(party *Party) *Card { return party.Players[0].Vanguard.TopCard }
+	rearGuard := func(party *Party) *Card { return party.Players[0].Rear1.TopCard }
+	runConditionCases(t, party, []conditionCase{
+		{name: "vanguard named", condition: HasUnitInVanguard("blaster"), want: true},
+		{name: "vanguard not named", condition: HasUnitInVanguard("Wingal"), want: false},
+		{name: "any vanguard", condition: HasUnitInVanguard(""), want: true},
+		{name: "vanguard grade exactly N", condition: VanguardGradeAtLeast(3), want: true},
+		{name: "vanguard grade N-1", condition: VanguardGradeAtLeast(2), want: true},
+		{name: "vanguard grade N+1", condition: VanguardGradeAtLeast(4), want: false},
+		{name: "opponent vanguard grade 3", condition: VanguardGradeAtLeast(3), player: 1, want: false},
+		{name: "opponent vanguard grade 3 on their turn", condition: Opponent(VanguardGradeAtLeast(3)), player: 1, turn: 2, want: true},
+		{name: "vanguard clan", condition: VanguardHasClan("royal paladin"), want: true},
+		{name: "vanguard other clan", condition: VanguardHasClan("Kagero"), want: false},
+		{name: "rear-guard named", condition: HasRearGuardNamed("Gancelot"), want: true},
+		{name: "vanguard is no rear-guard", condition: HasRearGuardNamed("Blaster"), want: false},
+		{name: "rear-guard clan", condition: HasRearGuardWithClan("Gold Paladin"), want: true},
+		{name: "back row rear-guard clan", condition: HasRearGuardWithClan("Kagero"), player: 1, want: true},
+		{name: "no rear-guard of clan", condition: HasRearGuardWithClan("Nova Grappler"), want: false},
+		{name: "rear-guards exactly N", condition: RearGuardsAtLeast(AnyCard(), 2), want: true},
+		{name: "rear-guards N+1", condition: RearGuardsAtLeast(AnyCard(), 3), want: false},
+		{name: "grade 2 rear-guards", condition: RearGuardsAtLeast(IsGrade(2), 1), want: true},
+		{name: "on VC", condition: OnVanguardCircle(), source: vanguard, want: true},
+		{name: "not on VC", condition: OnVanguardCircle(), source: rearGuard, want: false},
+		{name: "on RC", condition: OnRearGuardCircle(), source: rearGuard, want: true},
+		{name: "not on RC", condition: OnRearGuardCircle(), source: vanguard, want: false},
+		{name: "source matches", condition: SourceMatches(IsGrade(1)), source: rearGuard, want: true},
+		{name: "no source", condition: SourceMatches(AnyCard()), want: false},
+		{name: "turn player", condition: IsTurnPlayer(), want: true},
+		{name: "not turn player on opponent's turn", condition: IsTurnPlayer(), turn: 2, want: false},
+		{name: "opponent is turn player on their turn", condition: Opponent(IsTurnPlayer()), turn: 2, want: true},
+	})
+}
+
+// Player 1's vanguard attacks player 0's vanguard with a boost, during player 1's turn.
+func TestBattleConditions(t *testing.T) {
+	party := testParty()
+	attacker := func(party *Party) *Card { return party.Players[1].Vanguard.TopCard }
+	booster := func(party *Party) *Card { return party.Players[1].Rear4.TopCard }
+	attacked := func(party *Party) *Card { return party.Players[0].Vanguard.TopCard }
+	other := func(party *Party) *Card { return party.Players[0].Rear1.TopCard }
+
+	runConditionCases(t, party, []conditionCase{
+		{name: "no battle", condition: InBattle(), turn: 2, want: false},
+		{name: "not attacking outside battle", condition: IsAttacking(), player: 1, source: attacker, turn: 2, want: false},
+	})
+
+	party.Battle = &Battle{
+		Attacker:       party.Players[1].Vanguard.TopCard,
+		AttackerCircle: CircleVanguard,
+		Target:         party.Players[0].Vanguard.TopCard,
+		TargetCircle:   CircleVanguard,
+		Booster:        party.Players[1].Rear4.TopCard,
+		BoosterCircle:  CircleRear4,
+		CannotBeHit:    map[string]bool{},
+	}
+	runConditionCases(t, party, []conditionCase{
+		{name: "in battle", condition: InBattle(), turn: 2, want: true},
+		{name: "attacking", condition: IsAttacking(), player: 1, source: attacker, turn: 2, want: true},
+		{name: "booster is not attacking", condition: IsAttacking(), player: 1, source: booster, turn: 2, want: false},
+		{name: "boosted", condition: IsBoosted(), player: 1, source: attacker, turn: 2, want: true},
+		{name: "boosting", condition: IsBoosting(), player: 1, source: booster, turn: 2, want: true},
+		{name: "attacked on opponent's turn", condition: IsAttacked(), source: attacked, turn: 2, want: true},
+		{name: "rear-guard not attacked", condition: IsAttacked(), source: other, turn: 2, want: false},
+		{name: "attacked by vanguard", condition: AttackedByVanguard(), source: attacked, turn: 2, want: true},
+		{name: "attacker grade exactly 2", condition: AttackerMatches(GradeOrGreater(2)), turn: 2, want: true},
+		{name: "attacker grade 3", condition: AttackerMatches(GradeOrGreater(3)), turn: 2, want: false},
+		{name: "attacked while not turn player", condition: And(IsAttacked(), Not(IsTurnPlayer())), source: attacked, turn: 2, want: true},
+		{name: "not hit yet", condition: IsHit(), turn: 2, want: false},
+	})
+
+	party.Battle.Hit = true
+	party.Battle.Booster = nil
+	runConditionCases(t, party, []conditionCase{
+		{name: "hit", condition: IsHit(), turn: 2, want: true},
+		{name: "not boosted", condition: IsBoosted(), player: 1, source: attacker, turn: 2, want: false},
+	})
+}
@@ -0,0 +1,100 @@
+package core
+
+import "strconv"
+
+// MaxEnergy is the most energy a player can hold.
+const MaxEnergy = 10
+
+// Cost is the COST [...] part of an ability, paid before its effect.
+type Cost struct {
+	CounterBlast int
+	SoulBlast    int
+	Discard      int
+	EnergyBlast  int
+}
+
+func (cost Cost) IsZero() bool {
+	return cost == Cost{}
+}
+
+func (cost Cost) String() string {
+	parts := ""
+	add := func(name string, count int) {
+		if count > 0 {
+			if parts != "" {
+				parts += ", "
+			}
+			parts += name + " " + strconv.Itoa(count)
+		}
+	}
+	add("Counter Blast", cost.CounterBlast)
+	add("Soul Blast", cost.SoulBlast)
+	add("Discard", cost.Discard)
+	add("Energy Blast", cost.EnergyBlast)
+	return "[" + parts + "]"
+}
+
+func faceUpDamage(player *Player) []*Card {
+	cards := []*Card{}
+	for _, card := range player.DamageZone {
+		if !card.FaceDown {
+			cards = append(cards, card)
+		}
+	}
+	return cards
+}
+
+// CanPay checks the player has enough resources for the cost.
+func (party *Party) CanPay(player *Player, cost Cost) bool {
+	return len(faceUpDamage(player)) >= cost.CounterBlast &&
+		len(player.Vanguard.Soul) >= cost.SoulBlast &&
+		len(player.Hand) >= cost.Discard &&
+		player.Energy >= cost.EnergyBlast
+}
+
+// PayCost asks the player which cards pay the cost, returns false if it cannot be paid.
+func (party *Party) PayCost(player *Player, cost Cost) bool {
+	if !party.CanPay(player, cost) {
+		return false
+	}
+
+	if cost.CounterBlast > 0 {
+		damage := faceUpDamage(player)
+		for _, index := range party.choose(player, Choice{Kind: ChoiceCost, Prompt: "Counter Blast " + strconv.Itoa(cost.CounterBlast), Options: cardOptions(damage), Min: cost.CounterBlast, Max: cost.CounterBlast}) {
+			damage[index].FaceDown = true
+			party.logCard(LogFlip, player, damage[index], "face down")
+		}
+	}
+
+	if cost.SoulBlast > 0 {
+		soul := append([]*Card{}, player.Vanguard.Soul...)
+		for _, index := range party.choose(player, Choice{Kind: ChoiceCost, Prompt: "Soul Blast " + strconv.Itoa(cost.SoulBlast), Options: cardOptions(soul), Min: cost.SoulBlast, Max: cost.SoulBlast}) {
+			removeFromZone(&player.Vanguard.Soul, soul[index])
+			player.DropZone = append(player.DropZone, soul[index])
+			party.logMove(player, soul[index], ZoneSoul, ZoneDrop, "")
+		}
+	}
+
+	if cost.Discard > 0 {
+		hand := append([]*Card{}, player.Hand...)
+		for _, index := range party.choose(player, Choice{Kind: ChoiceCost, Prompt: "Discard " + strconv.Itoa(cost.Discard), Options: cardOptions(hand), Min: cost.Discard, Max: cost.Discard}) {
+			removeFromZone(&player.Hand, hand[index])
+			player.DropZone = append(player.DropZone, hand[index])
+			party.logMove(player, hand[index], ZoneHand, ZoneDrop, "")
+		}
+	}
+
+	if cost.EnergyBlast > 0 {
+		player.Energy -= cost.EnergyBlast
+		party.log(LogEntry{Action: LogEnergy, Player: party.playerIndex(player), Amount: -cost.EnergyBlast})
+	}
+	return true
+}
+
+// ChargeEnergy adds energy up to MaxEnergy.
+func (player *Player) ChargeEnergy(amount int) {
+	player.Energy += amount
+	if player.Energy > MaxEnergy {
+		player.Energy = MaxEnergy
+	}
+}
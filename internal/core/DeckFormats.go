@@ -0,0 +1,353 @@
+package core
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// DeckFormat is one of the deck list formats we can import and export.
+type DeckFormat string
+
+const (
+	// FormatMarkdown is the tab-separated list used in decks/, see ParseDeck.
+	FormatMarkdown DeckFormat = "markdown"
+	// FormatText is a plain "4 DZ-BT01/110EN" list with optional section headers.
+	FormatText DeckFormat = "text"
+	// FormatJSON follows DeckListJSON.
+	FormatJSON DeckFormat = "json"
+	// FormatCode is a compact shareable string, see EncodeDeckCode.
+	FormatCode DeckFormat = "code"
+)
+
+// DeckCodePrefix starts every deck code, the suffix being the version of the encoding.
+const DeckCodePrefix = "VG1-"
+
+// MaxDeckCodeSize caps the decompressed deck list of a deck code.
+const MaxDeckCodeSize = 64 * 1024
+
+// DeckListJSON is the JSON deck schema.
+type DeckListJSON struct {
+	Name string             `json:"name,omitempty"`
+	Ride []DeckListJSONCard `json:"ride"`
+	Main []DeckListJSONCard `json:"main"`
+	G    []DeckListJSONCard `json:"g,omitempty"`
+}
+
+type DeckListJSONCard struct {
+	Count      int    `json:"count"`
+	CardNumber string `json:"card_number"`
+	Name       string `json:"name,omitempty"`
+}
+
+// DeckFormatFromPath guesses the format from the file extension, markdown by default.
+func DeckFormatFromPath(path string) DeckFormat {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".txt":
+		return FormatText
+	case ".json":
+		return FormatJSON
+	case ".vgcode", ".code":
+		return FormatCode
+	}
+	return FormatMarkdown
+}
+
+// LoadDeckFile parses a deck file in the format matching its extension.
+func LoadDeckFile(filePath string, options DeckParseOptions) (*Deck, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return ParseDeckFormat(file, DeckFormatFromPath(filePath), options)
+}
+
+// ParseDeckFormat parses a deck list in the given format.
+func ParseDeckFormat(reader io.Reader, format DeckFormat, options DeckParseOptions) (*Deck, error) {
+	switch format {
+	case FormatMarkdown, "":
+		return ParseDeck(reader, options)
+	case FormatText:
+		return ParseDeckText(reader, options)
+	case FormatJSON:
+		return ParseDeckJSON(reader, options)
+	case FormatCode:
+		content, err := io.ReadAll(reader)
+		if err != nil {
+			return nil, err
+		}
+		return ParseDeckCode(string(content), options)
+	}
+	return nil, errors.New("unknown deck format: " + string(format))
+}
+
+// ParseDeckText reads lines like "4 DZ-BT01/110EN" or "4x DZ-BT01/110EN Tolerance Wizard".
+// Lines without a quantity are section headers ("Ride Deck:", "# Main", "[G]"),
+// cards listed before any header go to the main deck.
+func ParseDeckText(reader io.Reader, options DeckParseOptions) (*Deck, error) {
+	errs := &deckErrors{collectAll: options.CollectAll}
+	entries := []deckEntry{}
+
+	scanner := bufio.NewScanner(reader)
+	currentSection := SectionMain
+	lineNumber := 0
+
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		count, err := parseQuantity(fields[0])
+		if err != nil && !startsWithDigit(fields[0]) {
+			name := strings.Trim(line, "#[]: \t")
+			section, ok := parseSectionName(name)
+			if !ok {
+				if errs.add(lineNumber, ReasonUnknownSection, name, line) {
+					break
+				}
+				continue
+			}
+			currentSection = section
+			continue
+		}
+		if err != nil {
+			if errs.add(lineNumber, err.Reason, err.Detail, line) {
+				break
+			}
+			continue
+		}
+		if len(fields) < 2 {
+			if errs.add(lineNumber, ReasonMissingField, "card number", line) {
+				break
+			}
+			continue
+		}
+
+		entries = append(entries, deckEntry{
+			Line:       lineNumber,
+			Section:    currentSection,
+			Count:      count,
+			CardNumber: fields[1],
+			Text:       line,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(errs.errors) > 0 && !errs.collectAll {
+		return nil, errs.err()
+	}
+
+	return buildDeck(entries, options, errs)
+}
+
+// ParseDeckJSON reads a DeckListJSON document.
+func ParseDeckJSON(reader io.Reader, options DeckParseOptions) (*Deck, error) {
+	var list DeckListJSON
+	if err := json.NewDecoder(reader).Decode(&list); err != nil {
+		return nil, err
+	}
+	return DeckFromJSON(list, options)
+}
+
+// DeckFromJSON resolves an already decoded DeckListJSON.
+func DeckFromJSON(list DeckListJSON, options DeckParseOptions) (*Deck, error) {
+	errs := &deckErrors{collectAll: options.CollectAll}
+	entries := []deckEntry{}
+
+	sections := []struct {
+		section DeckSection
+		cards   []DeckListJSONCard
+	}{
+		{SectionRide, list.Ride},
+		{SectionMain, list.Main},
+		{SectionG, list.G},
+	}
+	for _, s := range sections {
+		for i, card := range s.cards {
+			where := string(s.section) + "[" + strconv.Itoa(i) + "]"
+			switch {
+			case card.Count <= 0:
+				if errs.add(0, ReasonBadQuantity, where+": "+strconv.Itoa(card.Count), "") {
+					return nil, errs.err()
+				}
+			case strings.TrimSpace(card.CardNumber) == "":
+				if errs.add(0, ReasonMissingField, where+": card_number", "") {
+					return nil, errs.err()
+				}
+			default:
+				entries = append(entries, deckEntry{
+					Section:    s.section,
+					Count:      card.Count,
+					CardNumber: strings.TrimSpace(card.CardNumber),
+					Text:       where,
+				})
+			}
+		}
+	}
+
+	return buildDeck(entries, options, errs)
+}
+
+// ParseDeckCode decodes a code produced by EncodeDeckCode.
+func ParseDeckCode(code string, options DeckParseOptions) (*Deck, error) {
+	text, err := decodeDeckCode(code)
+	if err != nil {
+		return nil, err
+	}
+	return ParseDeckText(strings.NewReader(text), options)
+}
+
+func decodeDeckCode(code string) (string, error) {
+	code = strings.TrimSpace(code)
+	if !strings.HasPrefix(code, DeckCodePrefix) {
+		return "", errors.New("deck code must start with " + DeckCodePrefix)
+	}
+
+	compressed, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(code, DeckCodePrefix))
+	if err != nil {
+		return "", errors.New("invalid deck code: " + err.Error())
+	}
+
+	// A few bytes of code can inflate into gigabytes, stop past any real deck list
+	text, err := io.ReadAll(io.LimitReader(flate.NewReader(bytes.NewReader(compressed)), MaxDeckCodeSize+1))
+	if err != nil {
+		return "", errors.New("invalid deck code: " + err.Error())
+	}
+	if len(text) > MaxDeckCodeSize {
+		return "", errors.New("invalid deck code: deck list larger than " + strconv.Itoa(MaxDeckCodeSize) + " bytes")
+	}
+	return string(text), nil
+}
+
+// ExportDeck writes the deck in the given format.
+func ExportDeck(deck *Deck, format DeckFormat) (string, error) {
+	switch format {
+	case FormatMarkdown, "":
+		return ExportDeckMarkdown(deck), nil
+	case FormatText:
+		return ExportDeckText(deck), nil
+	case FormatJSON:
+		content, err := json.MarshalIndent(DeckToJSON(deck), "", "  ")
+		if err != nil {
+			return "", err
+		}
+		return string(content), nil
+	case FormatCode:
+		return EncodeDeckCode(deck)
+	}
+	return "", errors.New("unknown deck format: " + string(format))
+}
+
+// ExportDeckMarkdown writes the tab-separated format read by ParseDeck.
+func ExportDeckMarkdown(deck *Deck) string {
+	var builder strings.Builder
+	for _, section := range deckSections(deck) {
+		if len(section.cards) == 0 {
+			continue
+		}
+		builder.WriteString("# " + sectionTitle(section.section) + "\n")
+		for _, card := range groupCards(section.cards) {
+			builder.WriteString(strconv.Itoa(card.Count) + "x\t " + card.Name + "\t[D Format]\t" + card.CardNumber + "\n")
+		}
+	}
+	return builder.String()
+}
+
+// ExportDeckText writes the plain "4 DZ-BT01/110EN" format read by ParseDeckText.
+func ExportDeckText(deck *Deck) string {
+	var builder strings.Builder
+	for _, section := range deckSections(deck) {
+		if len(section.cards) == 0 {
+			continue
+		}
+		builder.WriteString(sectionTitle(section.section) + ":\n")
+		for _, card := range groupCards(section.cards) {
+			builder.WriteString(strconv.Itoa(card.Count) + " " + card.CardNumber + "\n")
+		}
+	}
+	return builder.String()
+}
+
+func DeckToJSON(deck *Deck) DeckListJSON {
+	return DeckListJSON{
+		Ride: groupCards(deck.RideDeck),
+		Main: groupCards(deck.MainDeck),
+		G:    groupCards(deck.GDeck),
+	}
+}
+
+// EncodeDeckCode compresses the plain text list into a URL-safe string.
+func EncodeDeckCode(deck *Deck) (string, error) {
+	var buffer bytes.Buffer
+	writer, err := flate.NewWriter(&buffer, flate.BestCompression)
+	if err != nil {
+		return "", err
+	}
+	if _, err := writer.Write([]byte(ExportDeckText(deck))); err != nil {
+		return "", err
+	}
+	if err := writer.Close(); err != nil {
+		return "", err
+	}
+	return DeckCodePrefix + base64.RawURLEncoding.EncodeToString(buffer.Bytes()), nil
+}
+
+type sectionCards struct {
+	section DeckSection
+	cards   []*Card
+}
+
+func deckSections(deck *Deck) []sectionCards {
+	return []sectionCards{
+		{SectionRide, deck.RideDeck},
+		{SectionMain, deck.MainDeck},
+		{SectionG, deck.GDeck},
+	}
+}
+
+func sectionTitle(section DeckSection) string {
+	switch section {
+	case SectionRide:
+		return "Ride"
+	case SectionG:
+		return "G"
+	}
+	return "Main"
+}
+
+// groupCards counts copies by card number, in order of first appearance.
+func groupCards(cards []*Card) []DeckListJSONCard {
+	grouped := []DeckListJSONCard{}
+	index := map[string]int{}
+	for _, card := range cards {
+		if card == nil {
+			continue
+		}
+		if i, exists := index[card.CardNumberFull]; exists {
+			grouped[i].Count++
+			continue
+		}
+		index[card.CardNumberFull] = len(grouped)
+		grouped = append(grouped, DeckListJSONCard{Count: 1, CardNumber: card.CardNumberFull, Name: card.Name})
+	}
+	return grouped
+}
+
+func startsWithDigit(field string) bool {
+	return field != "" && field[0] >= '0' && field[0] <= '9'
+}
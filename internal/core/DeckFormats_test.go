@@ -0,0 +1,128 @@
+package core
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/base64"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func testDeckText() string {
+	return "Ride Deck (4):\n1 TEST/000\n1 TEST/001\n1 TEST/002\n1 TEST/003\n" +
+		"Main Deck (12):\n4 TEST/001\nx4 TEST/T01\n4x TEST/T03\n"
+}
+
+func deckNumbers(cards []*Card) []string {
+	numbers := make([]string, len(cards))
+	for i, card := range cards {
+		numbers[i] = card.CardNumberFull
+	}
+	return numbers
+}
+
+func sameDeck(t *testing.T, got *Deck, want *Deck) {
+	t.Helper()
+	sections := []struct {
+		name      string
+		got, want []*Card
+	}{
+		{"ride", got.RideDeck, want.RideDeck},
+		{"main", got.MainDeck, want.MainDeck},
+		{"g", got.GDeck, want.GDeck},
+	}
+	for _, section := range sections {
+		if !slices.Equal(deckNumbers(section.got), deckNumbers(section.want)) {
+			t.Errorf("%s deck: got %v, want %v", section.name, deckNumbers(section.got), deckNumbers(section.want))
+		}
+	}
+}
+
+func TestParseDeckTextHeaders(t *testing.T) {
+	options := DeckParseOptions{Database: testCardDatabase()}
+	cases := []struct {
+		name string
+		list string
+		ride int
+		main int
+	}{
+		{"counted headers", testDeckText(), 4, 12},
+		{"no header", "4 TEST/001\n", 0, 4},
+		{"markdown headers", "# Ride\n1 TEST/000\n# Main\n2 TEST/001\n", 1, 2},
+		{"bracket headers", "[ride deck]\n1 TEST/000\n[MAIN]\n2 TEST/001\n", 1, 2},
+		{"quantity before header", "x4 TEST/001\n", 0, 4},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			deck, err := ParseDeckText(strings.NewReader(c.list), options)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(deck.RideDeck) != c.ride || len(deck.MainDeck) != c.main {
+				t.Errorf("got %d/%d cards, want %d/%d", len(deck.RideDeck), len(deck.MainDeck), c.ride, c.main)
+			}
+		})
+	}
+
+	if _, err := ParseDeckText(strings.NewReader("Side Deck (4):\n"), options); err == nil {
+		t.Error("unknown section accepted")
+	}
+}
+
+func TestDeckFormatsRoundTrip(t *testing.T) {
+	options := DeckParseOptions{Database: testCardDatabase()}
+	deck, err := ParseDeckText(strings.NewReader(testDeckText()), options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, format := range []DeckFormat{FormatMarkdown, FormatText, FormatJSON, FormatCode} {
+		t.Run(string(format), func(t *testing.T) {
+			exported, err := ExportDeck(deck, format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if format == FormatCode && !strings.HasPrefix(exported, DeckCodePrefix) {
+				t.Errorf("code %q does not start with %s", exported, DeckCodePrefix)
+			}
+			parsed, err := ParseDeckFormat(strings.NewReader(exported), format, options)
+			if err != nil {
+				t.Fatalf("%v\n%s", err, exported)
+			}
+			sameDeck(t, parsed, deck)
+		})
+	}
+}
+
+func TestParseDeckCodeErrors(t *testing.T) {
+	code := func(text string) string {
+		var buffer bytes.Buffer
+		writer, _ := flate.NewWriter(&buffer, flate.BestCompression)
+		writer.Write([]byte(text))
+		writer.Close()
+		return DeckCodePrefix + base64.RawURLEncoding.EncodeToString(buffer.Bytes())
+	}
+	options := DeckParseOptions{Database: testCardDatabase()}
+
+	cases := []struct {
+		name string
+		code string
+	}{
+		{"missing prefix", strings.TrimPrefix(code("4 TEST/001\n"), DeckCodePrefix)},
+		{"bad base64", DeckCodePrefix + "!!!"},
+		{"not deflate", DeckCodePrefix + base64.RawURLEncoding.EncodeToString([]byte("plain text"))},
+		{"larger than the cap", code(strings.Repeat("a", MaxDeckCodeSize+1))},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if _, err := ParseDeckCode(c.code, options); err == nil {
+				t.Error("code accepted")
+			}
+		})
+	}
+
+	if _, err := ParseDeckCode(code("4 TEST/001\n"+strings.Repeat(" ", MaxDeckCodeSize-11)), options); err != nil {
+		t.Errorf("list of exactly %d bytes rejected: %v", MaxDeckCodeSize, err)
+	}
+}
@@ -0,0 +1,294 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DeckRevision is one saved version of a library deck.
+type DeckRevision struct {
+	Number  int          `json:"number"`
+	SavedAt time.Time    `json:"saved_at"`
+	Note    string       `json:"note,omitempty"`
+	List    DeckListJSON `json:"list"`
+}
+
+// LibraryDeck is a named deck with its whole revision history, oldest first.
+type LibraryDeck struct {
+	Name      string         `json:"name"`
+	Tags      []string       `json:"tags"`
+	Revisions []DeckRevision `json:"revisions"`
+}
+
+// Latest returns the last revision, nil when the deck has none.
+func (deck *LibraryDeck) Latest() *DeckRevision {
+	if len(deck.Revisions) == 0 {
+		return nil
+	}
+	return &deck.Revisions[len(deck.Revisions)-1]
+}
+
+// Revision returns the given revision number, the latest one for 0.
+func (deck *LibraryDeck) Revision(number int) (*DeckRevision, error) {
+	if number == 0 {
+		if latest := deck.Latest(); latest != nil {
+			return latest, nil
+		}
+		return nil, errors.New("revision not found")
+	}
+	for i := range deck.Revisions {
+		if deck.Revisions[i].Number == number {
+			return &deck.Revisions[i], nil
+		}
+	}
+	return nil, errors.New("revision not found")
+}
+
+// DeckSummary is what List returns, without the revision contents.
+type DeckSummary struct {
+	Name     string    `json:"name"`
+	Tags     []string  `json:"tags"`
+	Revision int       `json:"revision"`
+	SavedAt  time.Time `json:"saved_at"`
+}
+
+// DeckDiffEntry is a card whose count changed between two revisions.
+type DeckDiffEntry struct {
+	Section    DeckSection `json:"section"`
+	CardNumber string      `json:"card_number"`
+	Name       string      `json:"name,omitempty"`
+	Before     int         `json:"before"`
+	After      int         `json:"after"`
+}
+
+// DeckLibrary stores decks per client ID, one JSON file per owner in Directory.
+type DeckLibrary struct {
+	Directory string
+	mutex     sync.Mutex
+}
+
+// Library is the deck library used by the websocket server.
+var Library = NewDeckLibrary("library")
+
+func NewDeckLibrary(directory string) *DeckLibrary {
+	return &DeckLibrary{Directory: directory}
+}
+
+func (lib *DeckLibrary) List(owner string) ([]DeckSummary, error) {
+	lib.mutex.Lock()
+	defer lib.mutex.Unlock()
+
+	decks, err := lib.load(owner)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := []DeckSummary{}
+	for _, deck := range decks {
+		latest := deck.Latest()
+		summaries = append(summaries, DeckSummary{
+			Name:     deck.Name,
+			Tags:     deck.Tags,
+			Revision: latest.Number,
+			SavedAt:  latest.SavedAt,
+		})
+	}
+	return summaries, nil
+}
+
+// Get returns a deck and the requested revision (0 for the latest).
+func (lib *DeckLibrary) Get(owner string, name string, revision int) (*LibraryDeck, *DeckRevision, error) {
+	lib.mutex.Lock()
+	defer lib.mutex.Unlock()
+
+	decks, err := lib.load(owner)
+	if err != nil {
+		return nil, nil, err
+	}
+	deck := findLibraryDeck(decks, name)
+	if deck == nil {
+		return nil, nil, errors.New("deck not found: " + name)
+	}
+	rev, err := deck.Revision(revision)
+	if err != nil {
+		return nil, nil, err
+	}
+	return deck, rev, nil
+}
+
+// Save adds a new revision of the named deck, creating it if needed.
+// Tags replace the current ones unless nil.
+func (lib *DeckLibrary) Save(owner string, name string, tags []string, deck *Deck, note string) (*DeckRevision, error) {
+	if name == "" {
+		return nil, errors.New("deck name is required")
+	}
+
+	lib.mutex.Lock()
+	defer lib.mutex.Unlock()
+
+	decks, err := lib.load(owner)
+	if err != nil {
+		return nil, err
+	}
+
+	libraryDeck := findLibraryDeck(decks, name)
+	if libraryDeck == nil {
+		decks = append(decks, LibraryDeck{Name: name, Tags: []string{}, Revisions: []DeckRevision{}})
+		libraryDeck = &decks[len(decks)-1]
+	}
+	if tags != nil {
+		libraryDeck.Tags = tags
+	}
+
+	number := 1
+	if len(libraryDeck.Revisions) > 0 {
+		number = libraryDeck.Latest().Number + 1
+	}
+	libraryDeck.Revisions = append(libraryDeck.Revisions, DeckRevision{
+		Number:  number,
+		SavedAt: time.Now().UTC(),
+		Note:    note,
+		List:    DeckToJSON(deck),
+	})
+	revision := *libraryDeck.Latest()
+
+	if err := lib.store(owner, decks); err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
+func (lib *DeckLibrary) Delete(owner string, name string) error {
+	lib.mutex.Lock()
+	defer lib.mutex.Unlock()
+
+	decks, err := lib.load(owner)
+	if err != nil {
+		return err
+	}
+	for i := range decks {
+		if decks[i].Name == name {
+			return lib.store(owner, append(decks[:i], decks[i+1:]...))
+		}
+	}
+	return errors.New("deck not found: " + name)
+}
+
+// Diff lists the cards whose count differs between two revisions of a deck.
+func (lib *DeckLibrary) Diff(owner string, name string, from int, to int) ([]DeckDiffEntry, error) {
+	deck, _, err := lib.Get(owner, name, 0)
+	if err != nil {
+		return nil, err
+	}
+	before, err := deck.Revision(from)
+	if err != nil {
+		return nil, err
+	}
+	after, err := deck.Revision(to)
+	if err != nil {
+		return nil, err
+	}
+	return DiffDeckLists(before.List, after.List), nil
+}
+
+// DiffDeckLists compares two deck lists section by section.
+func DiffDeckLists(before DeckListJSON, after DeckListJSON) []DeckDiffEntry {
+	diff := []DeckDiffEntry{}
+
+	sections := []struct {
+		section DeckSection
+		before  []DeckListJSONCard
+		after   []DeckListJSONCard
+	}{
+		{SectionRide, before.Ride, after.Ride},
+		{SectionMain, before.Main, after.Main},
+		{SectionG, before.G, after.G},
+	}
+	for _, s := range sections {
+		counts := map[string]*DeckDiffEntry{}
+		order := []string{}
+		entry := func(card DeckListJSONCard) *DeckDiffEntry {
+			if counts[card.CardNumber] == nil {
+				counts[card.CardNumber] = &DeckDiffEntry{Section: s.section, CardNumber: card.CardNumber, Name: card.Name}
+				order = append(order, card.CardNumber)
+			}
+			return counts[card.CardNumber]
+		}
+		for _, card := range s.before {
+			entry(card).Before += card.Count
+		}
+		for _, card := range s.after {
+			entry(card).After += card.Count
+		}
+		for _, number := range order {
+			if counts[number].Before != counts[number].After {
+				diff = append(diff, *counts[number])
+			}
+		}
+	}
+	return diff
+}
+
+func findLibraryDeck(decks []LibraryDeck, name string) *LibraryDeck {
+	for i := range decks {
+		if decks[i].Name == name {
+			return &decks[i]
+		}
+	}
+	return nil
+}
+
+func (lib *DeckLibrary) path(owner string) string {
+	return filepath.Join(lib.Directory, url.PathEscape(owner)+".json")
+}
+
+func (lib *DeckLibrary) load(owner string) ([]LibraryDeck, error) {
+	if owner == "" {
+		return nil, errors.New("owner is required")
+	}
+
+	content, err := os.ReadFile(lib.path(owner))
+	if errors.Is(err, os.ErrNotExist) {
+		return []LibraryDeck{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var decks []LibraryDeck
+	if err := json.Unmarshal(content, &decks); err != nil {
+		return nil, err
+	}
+	// Save never stores a deck without revision, such a file was edited by hand
+	for _, deck := range decks {
+		if len(deck.Revisions) == 0 {
+			return nil, errors.New(lib.path(owner) + ": deck " + deck.Name + " has no revision")
+		}
+	}
+	return decks, nil
+}
+
+func (lib *DeckLibrary) store(owner string, decks []LibraryDeck) error {
+	sort.Slice(decks, func(i, j int) bool { return decks[i].Name < decks[j].Name })
+
+	content, err := json.MarshalIndent(decks, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(lib.Directory, 0o755); err != nil {
+		return err
+	}
+
+	// Write then rename so a crash never leaves a half written library
+	tmp := lib.path(owner) + ".tmp"
+	if err := os.WriteFile(tmp, content, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, lib.path(owner))
+}
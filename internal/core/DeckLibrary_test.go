@@ -0,0 +1,56 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLibraryDeckWithoutRevision(t *testing.T) {
+	deck := &LibraryDeck{Name: "Empty"}
+	if deck.Latest() != nil {
+		t.Error("Latest of a deck without revision is not nil")
+	}
+	if _, err := deck.Revision(0); err == nil {
+		t.Error("Revision(0) of a deck without revision succeeded")
+	}
+
+	lib := NewDeckLibrary(t.TempDir())
+	content := `[{"name": "Empty", "tags": [], "revisions": []}]`
+	if err := os.WriteFile(filepath.Join(lib.Directory, "owner.json"), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := lib.List("owner"); err == nil || !strings.Contains(err.Error(), "no revision") {
+		t.Errorf("List got %v, want the deck rejected", err)
+	}
+	if _, _, err := lib.Get("owner", "Empty", 0); err == nil {
+		t.Error("Get of a deck without revision succeeded")
+	}
+}
+
+func TestLibraryRevisions(t *testing.T) {
+	lib := NewDeckLibrary(t.TempDir())
+	deck := &Deck{MainDeck: []*Card{{CardNumberFull: "TEST/001"}}}
+	for i := 0; i < 2; i++ {
+		if _, err := lib.Save("owner", "Deck", nil, deck, ""); err != nil {
+			t.Fatal(err)
+		}
+		deck.MainDeck = append(deck.MainDeck, &Card{CardNumberFull: "TEST/002"})
+	}
+
+	summaries, err := lib.List("owner")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(summaries) != 1 || summaries[0].Revision != 2 {
+		t.Fatalf("got %+v, want one deck at revision 2", summaries)
+	}
+	diff, err := lib.Diff("owner", "Deck", 1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diff) != 1 || diff[0].CardNumber != "TEST/002" || diff[0].Before != 0 || diff[0].After != 1 {
+		t.Errorf("got diff %+v, want one TEST/002 added", diff)
+	}
+}
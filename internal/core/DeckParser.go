@@ -0,0 +1,276 @@
+package core
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DeckSection identifies the part of a deck list a card belongs to.
+type DeckSection string
+
+const (
+	SectionRide DeckSection = "ride"
+	SectionMain DeckSection = "main"
+	SectionG    DeckSection = "g"
+)
+
+// DeckParseReason classifies a deck parsing failure.
+type DeckParseReason string
+
+const (
+	ReasonBadQuantity    DeckParseReason = "bad quantity"
+	ReasonMissingField   DeckParseReason = "missing field"
+	ReasonUnknownCard    DeckParseReason = "unknown card"
+	ReasonUnknownSection DeckParseReason = "unknown section"
+	ReasonInvalidCard    DeckParseReason = "invalid card data"
+)
+
+// DeckParseError points at the deck list line that could not be parsed.
+// Line is 0 for formats without lines (JSON, deck codes), Detail then names the entry.
+type DeckParseError struct {
+	Line   int
+	Reason DeckParseReason
+	Detail string
+	Text   string
+}
+
+func (e *DeckParseError) Error() string {
+	msg := string(e.Reason)
+	if e.Line > 0 {
+		msg = "line " + strconv.Itoa(e.Line) + ": " + msg
+	}
+	if e.Detail != "" {
+		msg += ": " + e.Detail
+	}
+	return msg
+}
+
+// DeckParseErrors is returned when DeckParseOptions.CollectAll is set.
+type DeckParseErrors []*DeckParseError
+
+func (errs DeckParseErrors) Error() string {
+	lines := make([]string, len(errs))
+	for i, err := range errs {
+		lines[i] = err.Error()
+	}
+	return strings.Join(lines, "\n")
+}
+
+type DeckParseOptions struct {
+	// Database used to resolve card numbers, DefaultCardDatabase when nil.
+	Database *CardDatabase
+	// CollectAll keeps parsing after an error and returns every error as DeckParseErrors.
+	CollectAll bool
+}
+
+// deckEntry is one "<count> x <card number>" line of a deck list, whatever its format.
+type deckEntry struct {
+	Line       int
+	Section    DeckSection
+	Count      int
+	CardNumber string
+	Text       string
+}
+
+// deckErrors gathers parse errors, reporting whether parsing should stop.
+type deckErrors struct {
+	collectAll bool
+	errors     DeckParseErrors
+}
+
+func (de *deckErrors) add(line int, reason DeckParseReason, detail string, text string) bool {
+	de.errors = append(de.errors, &DeckParseError{Line: line, Reason: reason, Detail: detail, Text: text})
+	return !de.collectAll
+}
+
+func (de *deckErrors) err() error {
+	if len(de.errors) == 0 {
+		return nil
+	}
+	if !de.collectAll {
+		return de.errors[0]
+	}
+	sort.SliceStable(de.errors, func(i, j int) bool { return de.errors[i].Line < de.errors[j].Line })
+	return de.errors
+}
+
+func ParseDeckFile(filePath string) (*Deck, error) {
+	return ParseDeckFileWithOptions(filePath, DeckParseOptions{})
+}
+
+func ParseDeckFileWithOptions(filePath string, options DeckParseOptions) (*Deck, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return ParseDeck(file, options)
+}
+
+// ParseDeck reads a deck list in the tab-separated markdown format used in decks/:
+//
+//	# Ride
+//	1x	 Card Name	[D Format]	DZ-TD01/005EN
+func ParseDeck(reader io.Reader, options DeckParseOptions) (*Deck, error) {
+	errs := &deckErrors{collectAll: options.CollectAll}
+	entries := []deckEntry{}
+
+	scanner := bufio.NewScanner(reader)
+	currentSection := DeckSection("")
+	lineNumber := 0
+
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+
+		// Ignorer les lignes vides
+		if line == "" {
+			continue
+		}
+
+		// Détection de la section
+		if strings.HasPrefix(line, "#") {
+			name := strings.TrimSpace(strings.TrimPrefix(line, "#"))
+			section, ok := parseSectionName(name)
+			if !ok {
+				currentSection = ""
+				if errs.add(lineNumber, ReasonUnknownSection, name, line) {
+					break
+				}
+				continue
+			}
+			currentSection = section
+			continue
+		}
+
+		if currentSection == "" {
+			if errs.add(lineNumber, ReasonUnknownSection, "card listed outside of a known section", line) {
+				break
+			}
+			continue
+		}
+
+		entry, err := parseCardLine(line)
+		if err != nil {
+			if errs.add(lineNumber, err.Reason, err.Detail, line) {
+				break
+			}
+			continue
+		}
+		entry.Line = lineNumber
+		entry.Section = currentSection
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(errs.errors) > 0 && !errs.collectAll {
+		return nil, errs.err()
+	}
+
+	return buildDeck(entries, options, errs)
+}
+
+// parseSectionName accepts "Ride", "Main", "G" with an optional "Deck" suffix
+// and card count ("Main Deck (50)").
+func parseSectionName(name string) (DeckSection, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if open := strings.LastIndex(name, "("); open >= 0 && strings.HasSuffix(name, ")") {
+		if _, err := strconv.Atoi(strings.TrimSpace(name[open+1 : len(name)-1])); err == nil {
+			name = strings.TrimSpace(name[:open])
+		}
+	}
+	name = strings.TrimSpace(strings.TrimSuffix(name, "deck"))
+
+	switch DeckSection(name) {
+	case SectionRide, SectionMain, SectionG:
+		return DeckSection(name), true
+	}
+	return "", false
+}
+
+// parseCardLine découpe la ligne "<count>x\t<name>\t<format>\t<card number>"
+func parseCardLine(line string) (deckEntry, *DeckParseError) {
+	fields := strings.Split(line, "\t")
+
+	count, err := parseQuantity(fields[0])
+	if err != nil {
+		return deckEntry{}, err
+	}
+
+	if len(fields) < 4 || strings.TrimSpace(fields[3]) == "" {
+		return deckEntry{}, &DeckParseError{Reason: ReasonMissingField, Detail: "card number"}
+	}
+
+	return deckEntry{
+		Count:      count,
+		CardNumber: strings.TrimSpace(fields[3]),
+		Text:       line,
+	}, nil
+}
+
+// parseQuantity reads counts written as "4x", "4" or "x4".
+func parseQuantity(field string) (int, *DeckParseError) {
+	field = strings.TrimSpace(field)
+	countStr := strings.TrimPrefix(strings.TrimSuffix(strings.ToLower(field), "x"), "x")
+
+	count, err := strconv.Atoi(strings.TrimSpace(countStr))
+	if err != nil || count <= 0 {
+		return 0, &DeckParseError{Reason: ReasonBadQuantity, Detail: strconv.Quote(field)}
+	}
+	return count, nil
+}
+
+// buildDeck resolves entries against the card database, one Card instance per copy.
+func buildDeck(entries []deckEntry, options DeckParseOptions, errs *deckErrors) (*Deck, error) {
+	database := options.Database
+	if database == nil {
+		var err error
+		database, err = DefaultCardDatabase()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sections := map[DeckSection][]*Card{}
+
+	for _, entry := range entries {
+		rawCard := database.Find(entry.CardNumber)
+		if rawCard == nil {
+			if errs.add(entry.Line, ReasonUnknownCard, entry.CardNumber, entry.Text) {
+				break
+			}
+			continue
+		}
+
+		card, err := rawCard.ToCard()
+		if err != nil {
+			if errs.add(entry.Line, ReasonInvalidCard, err.Error(), entry.Text) {
+				break
+			}
+			continue
+		}
+
+		// Converted once, each copy is its own instance
+		sections[entry.Section] = append(sections[entry.Section], card)
+		for i := 1; i < entry.Count; i++ {
+			sections[entry.Section] = append(sections[entry.Section], card.Clone())
+		}
+	}
+
+	if err := errs.err(); err != nil {
+		return nil, err
+	}
+
+	return &Deck{
+		RideDeck: append([]*Card{}, sections[SectionRide]...),
+		MainDeck: append([]*Card{}, sections[SectionMain]...),
+		GDeck:    append([]*Card{}, sections[SectionG]...),
+	}, nil
+}
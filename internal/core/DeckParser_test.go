@@ -0,0 +1,115 @@
+package core
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// testCardDatabase holds a small D format card pool: a ride line and triggers.
+func testCardDatabase() *CardDatabase {
+	unit := func(number string, grade int, power int) RawCard {
+		return RawCard{
+			CardNumberFull: number,
+			Name:           "Unit " + number,
+			Type:           "Normal Unit",
+			Nation:         "Dragon Empire",
+			Grade:          "Grade " + strconv.Itoa(grade),
+			Power:          "Power " + strconv.Itoa(power),
+			Critical:       "Critical 1",
+			Shield:         "Shield 5000",
+			Skill:          "Boost",
+			Clan:           "Test Clan",
+		}
+	}
+	trigger := func(number string, kind string) RawCard {
+		card := unit(number, 0, 5000)
+		card.Type = "Trigger Unit"
+		card.Shield = "Shield 15000"
+		card.Skill = kind + " Trigger"
+		return card
+	}
+	db := NewCardDatabase()
+	db.Add("test", []RawCard{
+		unit("TEST/000", 0, 6000),
+		unit("TEST/001", 1, 8000),
+		unit("TEST/002", 2, 10000),
+		unit("TEST/003", 3, 13000),
+		trigger("TEST/T01", "Critical"),
+		trigger("TEST/T02", "Draw"),
+		trigger("TEST/T03", "Heal"),
+	})
+	return db
+}
+
+func TestParseDeckErrorLines(t *testing.T) {
+	cases := []struct {
+		name   string
+		list   string
+		line   int
+		reason DeckParseReason
+	}{
+		{"bad quantity", "# Ride\n1x\tA\t[D]\tTEST/000\n\nzx\tB\t[D]\tTEST/001\n", 4, ReasonBadQuantity},
+		{"zero quantity", "# Main\n0x\tB\t[D]\tTEST/001\n", 2, ReasonBadQuantity},
+		{"missing card number", "# Main\n4x\tB\t[D]\n", 2, ReasonMissingField},
+		{"unknown card", "# Ride\n1x\tA\t[D]\tTEST/000\n# Main\n4x\tX\t[D]\tTEST/999\n", 4, ReasonUnknownCard},
+		{"unknown section", "# Ride\n1x\tA\t[D]\tTEST/000\n# Side\n", 3, ReasonUnknownSection},
+		{"card outside section", "\n4x\tB\t[D]\tTEST/001\n", 2, ReasonUnknownSection},
+	}
+	options := DeckParseOptions{Database: testCardDatabase()}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := ParseDeck(strings.NewReader(c.list), options)
+			var parseErr *DeckParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("got %v, want a *DeckParseError", err)
+			}
+			if parseErr.Line != c.line || parseErr.Reason != c.reason {
+				t.Errorf("got line %d %q, want line %d %q", parseErr.Line, parseErr.Reason, c.line, c.reason)
+			}
+		})
+	}
+}
+
+func TestParseDeckCollectAll(t *testing.T) {
+	list := "# Ride\n1x\tA\t[D]\tTEST/000\n# Main\nzx\tB\t[D]\tTEST/001\n4x\tX\t[D]\tTEST/999\n4x\tB\t[D]\n4x\tB\t[D]\tTEST/001\n"
+
+	_, err := ParseDeck(strings.NewReader(list), DeckParseOptions{Database: testCardDatabase()})
+	var first *DeckParseError
+	if !errors.As(err, &first) || first.Line != 4 {
+		t.Fatalf("got %v, want the error of line 4 only", err)
+	}
+
+	_, err = ParseDeck(strings.NewReader(list), DeckParseOptions{Database: testCardDatabase(), CollectAll: true})
+	var all DeckParseErrors
+	if !errors.As(err, &all) {
+		t.Fatalf("got %v, want DeckParseErrors", err)
+	}
+	want := []struct {
+		line   int
+		reason DeckParseReason
+	}{{4, ReasonBadQuantity}, {5, ReasonUnknownCard}, {6, ReasonMissingField}}
+	if len(all) != len(want) {
+		t.Fatalf("got %d errors, want %d:\n%v", len(all), len(want), all)
+	}
+	for i, w := range want {
+		if all[i].Line != w.line || all[i].Reason != w.reason {
+			t.Errorf("error %d: got line %d %q, want line %d %q", i, all[i].Line, all[i].Reason, w.line, w.reason)
+		}
+	}
+}
+
+func TestParseDeckSections(t *testing.T) {
+	list := "# Ride Deck\n1x\tA\t[D]\tTEST/000\n1x\tB\t[D]\tTEST/001\n# main\n4x\tB\t[D]\tTEST/001\n2x\tC\t[D]\tTEST/T01\n"
+	deck, err := ParseDeck(strings.NewReader(list), DeckParseOptions{Database: testCardDatabase()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deck.RideDeck) != 2 || len(deck.MainDeck) != 6 || len(deck.GDeck) != 0 {
+		t.Fatalf("got %d/%d/%d cards, want 2/6/0", len(deck.RideDeck), len(deck.MainDeck), len(deck.GDeck))
+	}
+	if deck.MainDeck[0] == deck.MainDeck[1] || deck.MainDeck[0].ID == deck.MainDeck[1].ID {
+		t.Error("copies share the same instance")
+	}
+}
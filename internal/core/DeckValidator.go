@@ -0,0 +1,227 @@
+package core
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// D Format deck construction limits.
+const (
+	MainDeckSize    = 50
+	MaxCopiesByName = 4
+	TriggerCount    = 16
+	MaxOverTriggers = 1
+	MaxHealTriggers = 4
+	MaxGDeckSize    = 16
+)
+
+// DeckRule names the construction rule a violation breaks.
+type DeckRule string
+
+const (
+	RuleUnknownCard  DeckRule = "unknown card"
+	RuleRideDeck     DeckRule = "ride deck"
+	RuleMainDeckSize DeckRule = "main deck size"
+	RuleCopyLimit    DeckRule = "copy limit"
+	RuleTriggerCount DeckRule = "trigger count"
+	RuleOverTrigger  DeckRule = "over trigger"
+	RuleHealTrigger  DeckRule = "heal trigger"
+	RuleNation       DeckRule = "nation"
+	RuleGDeck        DeckRule = "g deck"
+)
+
+type DeckViolation struct {
+	Rule    DeckRule
+	Message string
+}
+
+func (v DeckViolation) String() string {
+	return string(v.Rule) + ": " + v.Message
+}
+
+// DeckViolations lists every broken rule, it is nil for a legal deck.
+type DeckViolations []DeckViolation
+
+func (violations DeckViolations) Error() string {
+	lines := make([]string, len(violations))
+	for i, violation := range violations {
+		lines[i] = violation.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ValidateDeck checks a deck against the D Format construction rules and returns all violations.
+func ValidateDeck(deck *Deck) DeckViolations {
+	var violations DeckViolations
+	add := func(rule DeckRule, message string) {
+		violations = append(violations, DeckViolation{Rule: rule, Message: message})
+	}
+
+	if deck == nil {
+		add(RuleUnknownCard, "deck is nil")
+		return violations
+	}
+
+	sections := []struct {
+		name  string
+		cards []*Card
+	}{
+		{"ride deck", deck.RideDeck},
+		{"main deck", deck.MainDeck},
+		{"g deck", deck.GDeck},
+	}
+	for _, section := range sections {
+		for i, card := range section.cards {
+			if card == nil {
+				add(RuleUnknownCard, section.name+" card #"+strconv.Itoa(i+1)+" is missing")
+			}
+		}
+	}
+
+	validateRideDeck(deck.RideDeck, add)
+
+	// Main deck size and triggers
+	if len(deck.MainDeck) != MainDeckSize {
+		add(RuleMainDeckSize, "main deck has "+strconv.Itoa(len(deck.MainDeck))+" cards, expected "+strconv.Itoa(MainDeckSize))
+	}
+
+	triggers, overs, heals := 0, 0, 0
+	for _, card := range deck.MainDeck {
+		if card == nil || card.CardType != CardTypeTriggerUnit {
+			continue
+		}
+		triggers++
+		switch card.Trigger.Type {
+		case TriggerOver:
+			overs++
+		case TriggerHeal:
+			heals++
+		}
+	}
+	if triggers != TriggerCount {
+		add(RuleTriggerCount, "main deck has "+strconv.Itoa(triggers)+" triggers, expected "+strconv.Itoa(TriggerCount))
+	}
+	if overs > MaxOverTriggers {
+		add(RuleOverTrigger, strconv.Itoa(overs)+" over triggers, at most "+strconv.Itoa(MaxOverTriggers)+" allowed")
+	}
+	if heals > MaxHealTriggers {
+		add(RuleHealTrigger, strconv.Itoa(heals)+" heal triggers, at most "+strconv.Itoa(MaxHealTriggers)+" allowed")
+	}
+
+	// Copies are counted by name across the ride deck and the main deck
+	copies := map[string]int{}
+	for _, card := range append(append([]*Card{}, deck.RideDeck...), deck.MainDeck...) {
+		if card != nil {
+			copies[card.Name]++
+		}
+	}
+	for _, name := range sortedKeys(copies) {
+		if copies[name] > MaxCopiesByName {
+			add(RuleCopyLimit, strconv.Itoa(copies[name])+" copies of "+name+", at most "+strconv.Itoa(MaxCopiesByName)+" allowed")
+		}
+	}
+
+	validateGDeck(deck.GDeck, add)
+
+	validateNation(append(append(append([]*Card{}, deck.RideDeck...), deck.MainDeck...), deck.GDeck...), add)
+
+	return violations
+}
+
+// validateRideDeck expects exactly one unit of each grade from 0 to 3, crests may be added.
+func validateRideDeck(rideDeck []*Card, add func(DeckRule, string)) {
+	grades := map[int]int{}
+	for _, card := range rideDeck {
+		if card == nil {
+			continue
+		}
+		switch {
+		case card.CardType == CardTypeCrest:
+			continue
+		case !card.CardType.IsUnit():
+			add(RuleRideDeck, card.Name+" is not a unit")
+		case card.Grade < 0 || card.Grade > 3:
+			add(RuleRideDeck, card.Name+" is grade "+strconv.Itoa(card.Grade)+", ride deck holds grades 0 to 3")
+		default:
+			grades[card.Grade]++
+		}
+	}
+	for grade := 0; grade <= 3; grade++ {
+		switch count := grades[grade]; {
+		case count == 0:
+			add(RuleRideDeck, "missing a grade "+strconv.Itoa(grade)+" unit")
+		case count > 1:
+			add(RuleRideDeck, strconv.Itoa(count)+" grade "+strconv.Itoa(grade)+" units, expected 1")
+		}
+	}
+}
+
+func validateGDeck(gDeck []*Card, add func(DeckRule, string)) {
+	if len(gDeck) > MaxGDeckSize {
+		add(RuleGDeck, "g deck has "+strconv.Itoa(len(gDeck))+" cards, at most "+strconv.Itoa(MaxGDeckSize)+" allowed")
+	}
+
+	copies := map[string]int{}
+	for _, card := range gDeck {
+		if card == nil {
+			continue
+		}
+		if card.CardType != CardTypeGUnit {
+			add(RuleGDeck, card.Name+" is not a G unit")
+		}
+		copies[card.Name]++
+	}
+	for _, name := range sortedKeys(copies) {
+		if copies[name] > MaxCopiesByName {
+			add(RuleGDeck, strconv.Itoa(copies[name])+" copies of "+name+", at most "+strconv.Itoa(MaxCopiesByName)+" allowed")
+		}
+	}
+}
+
+// validateNation requires every card with a nation to share at least one nation with the others.
+func validateNation(cards []*Card, add func(DeckRule, string)) {
+	var common map[string]bool
+	for _, card := range cards {
+		nations := cardNations(card)
+		if len(nations) == 0 {
+			continue
+		}
+		if common == nil {
+			common = nations
+			continue
+		}
+		for nation := range common {
+			if !nations[nation] {
+				delete(common, nation)
+			}
+		}
+		if len(common) == 0 {
+			add(RuleNation, card.Name+" ("+strings.Join(card.Nation, "/")+") does not share a nation with the rest of the deck")
+			return
+		}
+	}
+}
+
+func cardNations(card *Card) map[string]bool {
+	nations := map[string]bool{}
+	if card == nil || card.CardType == CardTypeCrest {
+		return nations
+	}
+	for _, nation := range card.Nation {
+		nation = strings.TrimSpace(nation)
+		if nation != "" && nation != "-" {
+			nations[nation] = true
+		}
+	}
+	return nations
+}
+
+func sortedKeys(counts map[string]int) []string {
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
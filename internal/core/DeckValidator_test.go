@@ -0,0 +1,115 @@
+package core
+
+import (
+	"slices"
+	"strconv"
+	"testing"
+)
+
+func deckCard(name string, cardType CardType, grade int, trigger TriggerType) *Card {
+	return &Card{Name: name, CardNumberFull: name, CardType: cardType, Grade: grade, Nation: []string{"Dragon Empire"}, Trigger: TriggerInfo{Type: trigger}}
+}
+
+func deckCopies(count int, name string, cardType CardType, grade int, trigger TriggerType) []*Card {
+	cards := make([]*Card, count)
+	for i := range cards {
+		cards[i] = deckCard(name, cardType, grade, trigger)
+	}
+	return cards
+}
+
+// legalDeck is a D Format deck: a grade 0 to 3 ride deck, 34 normal units and 16 triggers,
+// 4 of them heals.
+func legalDeck() *Deck {
+	deck := &Deck{}
+	for grade := 0; grade <= 3; grade++ {
+		deck.RideDeck = append(deck.RideDeck, deckCard("Ride "+strconv.Itoa(grade), CardTypeNormalUnit, grade, ""))
+	}
+	for i := 0; i < 8; i++ {
+		deck.MainDeck = append(deck.MainDeck, deckCopies(4, "Unit "+strconv.Itoa(i), CardTypeNormalUnit, 1+i%3, "")...)
+	}
+	deck.MainDeck = append(deck.MainDeck, deckCopies(2, "Unit 8", CardTypeNormalUnit, 3, "")...)
+	deck.MainDeck = append(deck.MainDeck, deckCopies(4, "Critical A", CardTypeTriggerUnit, 0, TriggerCritical)...)
+	deck.MainDeck = append(deck.MainDeck, deckCopies(4, "Critical B", CardTypeTriggerUnit, 0, TriggerCritical)...)
+	deck.MainDeck = append(deck.MainDeck, deckCopies(4, "Draw", CardTypeTriggerUnit, 0, TriggerDraw)...)
+	deck.MainDeck = append(deck.MainDeck, deckCopies(4, "Heal", CardTypeTriggerUnit, 0, TriggerHeal)...)
+	return deck
+}
+
+func TestValidateDeck(t *testing.T) {
+	cases := []struct {
+		name   string
+		change func(deck *Deck)
+		want   []DeckRule
+	}{
+		{"legal", func(deck *Deck) {}, nil},
+		{"main deck 49", func(deck *Deck) { deck.MainDeck = deck.MainDeck[1:] }, []DeckRule{RuleMainDeckSize}},
+		{"main deck 51", func(deck *Deck) {
+			deck.MainDeck = append(deck.MainDeck, deckCard("Unit 8", CardTypeNormalUnit, 3, ""))
+		}, []DeckRule{RuleMainDeckSize}},
+		{"4 copies", func(deck *Deck) {
+			deck.MainDeck[0] = deckCard("Ride 1", CardTypeNormalUnit, 1, "")
+			deck.MainDeck[1] = deckCard("Ride 1", CardTypeNormalUnit, 1, "")
+			deck.MainDeck[2] = deckCard("Ride 1", CardTypeNormalUnit, 1, "")
+		}, nil},
+		{"5 copies counting the ride deck", func(deck *Deck) {
+			for i := 0; i < 4; i++ {
+				deck.MainDeck[i] = deckCard("Ride 1", CardTypeNormalUnit, 1, "")
+			}
+		}, []DeckRule{RuleCopyLimit}},
+		{"15 triggers", func(deck *Deck) {
+			deck.MainDeck[len(deck.MainDeck)-1] = deckCard("Unit 8", CardTypeNormalUnit, 3, "")
+		}, []DeckRule{RuleTriggerCount}},
+		{"17 triggers", func(deck *Deck) {
+			deck.MainDeck[0] = deckCard("Over", CardTypeTriggerUnit, 0, TriggerOver)
+		}, []DeckRule{RuleTriggerCount}},
+		{"1 over trigger", func(deck *Deck) {
+			deck.MainDeck[len(deck.MainDeck)-5] = deckCard("Over", CardTypeTriggerUnit, 0, TriggerOver)
+		}, nil},
+		{"2 over triggers", func(deck *Deck) {
+			deck.MainDeck[len(deck.MainDeck)-5] = deckCard("Over", CardTypeTriggerUnit, 0, TriggerOver)
+			deck.MainDeck[len(deck.MainDeck)-6] = deckCard("Over", CardTypeTriggerUnit, 0, TriggerOver)
+		}, []DeckRule{RuleOverTrigger}},
+		{"5 heal triggers", func(deck *Deck) {
+			deck.MainDeck[len(deck.MainDeck)-5] = deckCard("Heal B", CardTypeTriggerUnit, 0, TriggerHeal)
+		}, []DeckRule{RuleHealTrigger}},
+		{"ride deck missing grade 3", func(deck *Deck) { deck.RideDeck = deck.RideDeck[:3] }, []DeckRule{RuleRideDeck}},
+		{"ride deck with two grade 2", func(deck *Deck) {
+			deck.RideDeck[3] = deckCard("Ride 2b", CardTypeNormalUnit, 2, "")
+		}, []DeckRule{RuleRideDeck, RuleRideDeck}},
+		{"crest in ride deck", func(deck *Deck) {
+			deck.RideDeck = append(deck.RideDeck, &Card{Name: "Crest", CardType: CardTypeCrest, Grade: NoGrade})
+		}, nil},
+		{"other nation", func(deck *Deck) { deck.MainDeck[0].Nation = []string{"Keter Sanctuary"} }, []DeckRule{RuleNation}},
+		{"shared nation", func(deck *Deck) { deck.MainDeck[0].Nation = []string{"Keter Sanctuary", "Dragon Empire"} }, nil},
+		{"16 g units", func(deck *Deck) {
+			for i := 0; i < 4; i++ {
+				deck.GDeck = append(deck.GDeck, deckCopies(4, "G "+strconv.Itoa(i), CardTypeGUnit, 4, "")...)
+			}
+		}, nil},
+		{"17 g units", func(deck *Deck) {
+			for i := 0; i < 4; i++ {
+				deck.GDeck = append(deck.GDeck, deckCopies(4, "G "+strconv.Itoa(i), CardTypeGUnit, 4, "")...)
+			}
+			deck.GDeck = append(deck.GDeck, deckCard("G 4", CardTypeGUnit, 4, ""))
+		}, []DeckRule{RuleGDeck}},
+		{"normal unit in g deck", func(deck *Deck) {
+			deck.GDeck = append(deck.GDeck, deckCard("G", CardTypeNormalUnit, 3, ""))
+		}, []DeckRule{RuleGDeck}},
+		{"missing card", func(deck *Deck) { deck.MainDeck[0] = nil }, []DeckRule{RuleUnknownCard}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			deck := legalDeck()
+			c.change(deck)
+			violations := ValidateDeck(deck)
+			rules := []DeckRule{}
+			for _, violation := range violations {
+				rules = append(rules, violation.Rule)
+			}
+			if !slices.Equal(rules, c.want) && !(len(rules) == 0 && len(c.want) == 0) {
+				t.Errorf("got %v, want %v\n%v", rules, c.want, violations)
+			}
+		})
+	}
+}
@@ -1,36 +1,493 @@
 package core
 
-import "fmt"
+import (
+	"fmt"
+	"strconv"
+)
 
-// EffectAction represents the execution of an effect.
-type EffectAction func(party *Party, player *Player, source *Card)
+// EffectAction represents the execution of an effect. It reports whether the effect
+// actually did something, which "if you do" continuations depend on.
+type EffectAction func(party *Party, player *Player, source *Card) bool
+
+// CardSelector picks the cards an effect applies to.
+type CardSelector func(party *Party, player *Player, source *Card) []*Card
+
+// ThisCard selects the card whose ability resolves.
+func ThisCard() CardSelector {
+	return func(party *Party, player *Player, source *Card) []*Card {
+		if source == nil {
+			return nil
+		}
+		return []*Card{source}
+	}
+}
+
+// Counter computes how many times a "for each" effect repeats.
+type Counter func(party *Party, player *Player, source *Card) int
+
+// Sequencing
+
+// Then runs the steps one after the other whatever happens ("..., then ..."),
+// it reports whether any step did something.
+func Then(steps ...EffectAction) EffectAction {
+	return func(party *Party, player *Player, source *Card) bool {
+		done := false
+		for _, step := range steps {
+			if step != nil && step(party, player, source) {
+				done = true
+			}
+		}
+		return done
+	}
+}
+
+// IfYouDo runs the continuation only when the first effect did something
+// ("Counter Blast 1, and if you do, draw a card").
+func IfYouDo(first EffectAction, continuation EffectAction) EffectAction {
+	return func(party *Party, player *Player, source *Card) bool {
+		if !first(party, player, source) {
+			return false
+		}
+		return continuation(party, player, source)
+	}
+}
+
+// YouMay asks the player whether to perform an optional effect ("you may ...").
+// Without an answer the effect is declined.
+func YouMay(prompt string, effect EffectAction) EffectAction {
+	return func(party *Party, player *Player, source *Card) bool {
+		chosen := party.choose(player, Choice{Kind: ChoiceOptional, Prompt: prompt, Options: []string{"Yes"}, Min: 0, Max: 1})
+		if len(chosen) == 0 {
+			return false
+		}
+		return effect(party, player, source)
+	}
+}
+
+// OnlyIf runs the effect when the condition is met ("if your vanguard is grade 3 or greater, ...").
+func OnlyIf(condition Condition, effect EffectAction) EffectAction {
+	return func(party *Party, player *Player, source *Card) bool {
+		if !condition(party, player, source) {
+			return false
+		}
+		return effect(party, player, source)
+	}
+}
+
+// ForEach repeats the effect once per counted item ("for each of your rear-guards, ...").
+// The count is taken before the first repetition.
+func ForEach(counter Counter, effect EffectAction) EffectAction {
+	return func(party *Party, player *Player, source *Card) bool {
+		done := false
+		for i := counter(party, player, source); i > 0; i-- {
+			if effect(party, player, source) {
+				done = true
+			}
+		}
+		return done
+	}
+}
+
+// Repeat runs the effect 'times' times.
+func Repeat(times int, effect EffectAction) EffectAction {
+	return ForEach(Fixed(times), effect)
+}
+
+// Fixed counts a constant.
+func Fixed(count int) Counter {
+	return func(party *Party, player *Player, source *Card) int {
+		return count
+	}
+}
+
+// CountTargets counts the cards a target could be, "for each of your grade 1 rear-guards".
+func CountTargets(target Target) Counter {
+	return func(party *Party, player *Player, source *Card) int {
+		return len(target.Candidates(party, player))
+	}
+}
+
+// CountZone counts the cards in one of the player's zones matching the filter.
+func CountZone(zone Zone, filter CardFilter) Counter {
+	return func(party *Party, player *Player, source *Card) int {
+		cards := player.zone(zone)
+		if cards == nil {
+			return 0
+		}
+		return len(filterCards(*cards, filter))
+	}
+}
+
+// PayCostEffect pays a cost as part of the effect, the step fails when it cannot be paid.
+// Combined with YouMay and IfYouDo: "you may Counter Blast 1, and if you do, ...".
+func PayCostEffect(cost Cost) EffectAction {
+	return func(party *Party, player *Player, source *Card) bool {
+		return party.PayCost(player, cost)
+	}
+}
 
 // Common Effects
 
 // DrawEffect returns an effect that draws 'count' cards.
 func DrawEffect(count int) EffectAction {
-	return func(party *Party, player *Player, source *Card) {
+	return func(party *Party, player *Player, source *Card) bool {
 		fmt.Printf("Effect: Drawing %d card(s) for Player\n", count)
-		draw(player, count)
+		return party.Draw(player, count)
 	}
 }
 
-// PowerUpEffect returns an effect that increases power of the source card (if active).
+// PowerUpEffect returns an effect that increases power of the source card until end of turn.
 func PowerUpEffect(amount int) EffectAction {
-	return func(party *Party, player *Player, source *Card) {
+	return func(party *Party, player *Player, source *Card) bool {
+		if source == nil {
+			return false
+		}
+		fmt.Printf("Effect: Power +%d to %s\n", amount, source.Name)
+		party.AddModifier(source, StatPower, amount, UntilEndOfTurn, source)
+		return true
+	}
+}
+
+// ModifyStatEffect gives the selected units +amount (or -amount) to a stat until end of turn.
+// "Choose one of your rear-guards, and it gets [Power]+5000 until end of turn."
+func ModifyStatEffect(selector CardSelector, stat Stat, amount int) EffectAction {
+	return func(party *Party, player *Player, source *Card) bool {
+		cards := selector(party, player, source)
+		for _, card := range cards {
+			fmt.Printf("Effect: %s %+d to %s\n", stat, amount, card.Name)
+			party.AddModifier(card, stat, amount, UntilEndOfTurn, source)
+		}
+		return len(cards) > 0
+	}
+}
+
+// DriveModifierEffect changes the drive count of the player's vanguard until end of turn.
+func DriveModifierEffect(amount int) EffectAction {
+	return func(party *Party, player *Player, source *Card) bool {
+		vanguard := player.Vanguard.TopCard
+		if vanguard == nil {
+			return false
+		}
+		fmt.Printf("Effect: Drive %+d to %s\n", amount, vanguard.Name)
+		party.AddModifier(vanguard, StatDrive, amount, UntilEndOfTurn, source)
+		return true
+	}
+}
+
+// CannotBeHitEffect makes the source unit impossible to hit until end of the battle.
+func CannotBeHitEffect() EffectAction {
+	return func(party *Party, player *Player, source *Card) bool {
+		if source == nil || party.Battle == nil {
+			return false
+		}
+		fmt.Printf("Effect: %s cannot be hit\n", source.Name)
+		party.PreventHit(source)
+		return true
+	}
+}
+
+// CannotBeRetiredEffect prevents the selected rear-guards from being retired until end of turn.
+func CannotBeRetiredEffect(selector CardSelector) EffectAction {
+	return func(party *Party, player *Player, source *Card) bool {
+		sourceID := ""
 		if source != nil {
-			fmt.Printf("Effect: Power +%d to %s\n", amount, source.Name)
-			source.Power += amount
-			// Note: In a real implementation this would likely be a temporary +Power on the Circle/Game State,
-			// not modifying the base card struct permanently unless intended.
-			// But for this simplified version, we modify the struct.
+			sourceID = source.ID
+		}
+		cards := selector(party, player, source)
+		for _, card := range cards {
+			protected := card
+			fmt.Printf("Effect: %s cannot be retired\n", protected.Name)
+			party.AddReplacement(Replacement{
+				Action:   ActionRetire,
+				Duration: UntilEndOfTurn,
+				SourceID: sourceID,
+				Applies: func(party *Party, action *PendingAction) bool {
+					return action.Card == protected
+				},
+				Replace: func(party *Party, action *PendingAction) {
+					action.Cancelled = true
+				},
+			})
+		}
+		return len(cards) > 0
+	}
+}
+
+// PreventDamageEffect reduces the next damage dealt to your vanguard this turn by amount.
+func PreventDamageEffect(amount int) EffectAction {
+	return func(party *Party, player *Player, source *Card) bool {
+		if amount <= 0 {
+			return false
+		}
+		sourceID := ""
+		if source != nil {
+			sourceID = source.ID
+		}
+		fmt.Printf("Effect: the next damage is reduced by %d\n", amount)
+		party.AddReplacement(Replacement{
+			Action:   ActionDamage,
+			Duration: UntilEndOfTurn,
+			SourceID: sourceID,
+			Once:     true,
+			Applies: func(party *Party, action *PendingAction) bool {
+				return action.Player == player
+			},
+			Replace: func(party *Party, action *PendingAction) {
+				action.Count = max(action.Count-amount, 0)
+				action.Cancelled = action.Count == 0
+			},
+		})
+		return true
+	}
+}
+
+// RetireUnitEffect retires the selected rear-guards.
+func RetireUnitEffect(selector CardSelector) EffectAction {
+	return func(party *Party, player *Player, source *Card) bool {
+		done := false
+		for _, card := range selector(party, player, source) {
+			if party.Retire(card) {
+				done = true
+			}
+		}
+		return done
+	}
+}
+
+// BindEffect puts the selected cards into the bind zone, face up or face down.
+func BindEffect(selector CardSelector, faceDown bool) EffectAction {
+	return func(party *Party, player *Player, source *Card) bool {
+		done := false
+		for _, card := range selector(party, player, source) {
+			if party.Bind(card, faceDown) {
+				done = true
+			}
+		}
+		return done
+	}
+}
+
+// LockEffect locks the selected rear-guards.
+func LockEffect(selector CardSelector) EffectAction {
+	return func(party *Party, player *Player, source *Card) bool {
+		done := false
+		for _, card := range selector(party, player, source) {
+			if party.Lock(card) {
+				done = true
+			}
+		}
+		return done
+	}
+}
+
+// UnlockEffect unlocks the selected rear-guards.
+func UnlockEffect(selector CardSelector) EffectAction {
+	return func(party *Party, player *Player, source *Card) bool {
+		done := false
+		for _, card := range selector(party, player, source) {
+			if party.Unlock(card) {
+				done = true
+			}
+		}
+		return done
+	}
+}
+
+// ReturnToHandEffect returns the selected cards to their owner's hand.
+func ReturnToHandEffect(selector CardSelector) EffectAction {
+	return func(party *Party, player *Player, source *Card) bool {
+		done := false
+		for _, card := range selector(party, player, source) {
+			if party.ReturnToHand(card) {
+				done = true
+			}
+		}
+		return done
+	}
+}
+
+// PutIntoDeckEffect puts the selected cards on the top, or at the bottom, of their owner's deck.
+func PutIntoDeckEffect(selector CardSelector, bottom bool) EffectAction {
+	return func(party *Party, player *Player, source *Card) bool {
+		done := false
+		for _, card := range selector(party, player, source) {
+			if party.PutIntoDeck(card, bottom) {
+				done = true
+			}
+		}
+		return done
+	}
+}
+
+// EnergyChargeEffect charges 'amount' energy, up to MaxEnergy.
+func EnergyChargeEffect(amount int) EffectAction {
+	return func(party *Party, player *Player, source *Card) bool {
+		before := player.Energy
+		player.ChargeEnergy(amount)
+		fmt.Printf("Effect: Energy Charge %d (%d)\n", amount, player.Energy)
+		if player.Energy > before {
+			party.log(LogEntry{Action: LogEnergy, Player: party.playerIndex(player), Amount: player.Energy - before})
+		}
+		return player.Energy > before
+	}
+}
+
+// SoulChargeEffect puts the top 'count' cards of the deck into the soul.
+func SoulChargeEffect(count int) EffectAction {
+	return func(party *Party, player *Player, source *Card) bool {
+		return party.SoulCharge(player, count) > 0
+	}
+}
+
+// CounterChargeEffect turns 'count' face down damage face up.
+func CounterChargeEffect(count int) EffectAction {
+	return func(party *Party, player *Player, source *Card) bool {
+		return party.CounterCharge(player, count) > 0
+	}
+}
+
+// SuperiorCallEffect lets the player call up to 'count' units from one of their zones
+// (deck, drop, soul...) to rear-guard circles of their choice.
+func SuperiorCallEffect(from Zone, count int) EffectAction {
+	return func(party *Party, player *Player, source *Card) bool {
+		zone := player.zone(from)
+		if zone == nil {
+			return false
+		}
+		units := []*Card{}
+		for _, card := range *zone {
+			if card.CardType.IsUnit() {
+				units = append(units, card)
+			}
+		}
+
+		called := 0
+		chosen := party.choose(player, Choice{Kind: ChoiceCard, Prompt: "Choose up to " + strconv.Itoa(count) + " unit(s) to call from " + string(from), Options: cardOptions(units), Min: 0, Max: count})
+		for _, index := range chosen {
+			rearGuards := rearGuardCircles()
+			options := make([]string, len(rearGuards))
+			for i, id := range rearGuards {
+				options[i] = string(id) + ": " + ToString(player.Circle(id).TopCard)
+			}
+			circle := party.choose(player, Choice{Kind: ChoiceCircle, Prompt: "Choose a circle for " + units[index].Name, Options: options, Min: 1, Max: 1})
+			if err := party.SuperiorCall(units[index], rearGuards[circle[0]]); err != nil {
+				fmt.Println("Effect: " + err.Error())
+				continue
+			}
+			called++
+		}
+		return called > 0
+	}
+}
+
+// RestPlacement is where the cards looked at but not chosen end up.
+type RestPlacement string
+
+const (
+	RestBottom  RestPlacement = "bottom"
+	RestTop     RestPlacement = "top"
+	RestDrop    RestPlacement = "drop"
+	RestShuffle RestPlacement = "shuffle"
+)
+
+// LookAtTopEffect looks at the top 'count' cards of the deck, lets the player choose up to 'pick'
+// of them matching the filter, reveals them and puts them into a zone ('to'), then places the rest.
+// "Look at the top five cards of your deck, choose up to one grade 3 from among them,
+// reveal it and put it into your hand, and put the rest on the bottom of your deck in any order."
+func LookAtTopEffect(count int, filter CardFilter, pick int, to Zone, rest RestPlacement) EffectAction {
+	return func(party *Party, player *Player, source *Card) bool {
+		// With fewer cards left, every card of the deck is looked at
+		looking := min(count, len(player.MainDeck))
+		looked := append([]*Card{}, player.MainDeck[:looking]...)
+		party.reveal(player, looked, false)
+
+		matching := filterCards(looked, filter)
+		chosen := party.choose(player, Choice{Kind: ChoiceCard, Prompt: "Choose up to " + strconv.Itoa(pick) + " card(s)", Options: cardOptions(matching), Min: 0, Max: pick})
+		picked := make([]*Card, len(chosen))
+		for i, index := range chosen {
+			picked[i] = matching[index]
+		}
+		party.reveal(player, picked, true)
+		for _, card := range picked {
+			party.MoveCard(card, to)
+		}
+
+		remaining := []*Card{}
+		for _, card := range looked {
+			if !containsCard(picked, card) {
+				remaining = append(remaining, card)
+			}
+		}
+		if rest == RestDrop {
+			for _, card := range remaining {
+				party.MoveCard(card, ZoneDrop)
+			}
+			return len(picked) > 0
+		}
+
+		for _, card := range remaining {
+			removeFromZone(&player.MainDeck, card)
+		}
+		switch rest {
+		case RestTop:
+			player.MainDeck = append(party.chooseOrder(player, remaining, "Choose the next card to put on top of your deck"), player.MainDeck...)
+		case RestShuffle:
+			player.MainDeck = append(player.MainDeck, remaining...)
+			party.Shuffle(player)
+		default:
+			player.MainDeck = append(player.MainDeck, party.chooseOrder(player, remaining, "Choose the next card to put on the bottom of your deck")...)
+		}
+		return len(picked) > 0
+	}
+}
+
+// SearchDeckEffect searches the deck for up to 'count' cards matching the filter, reveals them,
+// puts them into a zone ('to') and shuffles the deck.
+func SearchDeckEffect(filter CardFilter, count int, to Zone) EffectAction {
+	return func(party *Party, player *Player, source *Card) bool {
+		matching := filterCards(player.MainDeck, filter)
+		chosen := party.choose(player, Choice{Kind: ChoiceCard, Prompt: "Search your deck for up to " + strconv.Itoa(count) + " card(s)", Options: cardOptions(matching), Min: 0, Max: count})
+		picked := make([]*Card, len(chosen))
+		for i, index := range chosen {
+			picked[i] = matching[index]
+		}
+		party.reveal(player, picked, true)
+		for _, card := range picked {
+			party.MoveCard(card, to)
+		}
+		party.Shuffle(player)
+		return len(picked) > 0
+	}
+}
+
+// RevealTopEffect reveals the top 'count' cards of the deck to every player, they stay on the deck.
+func RevealTopEffect(count int) EffectAction {
+	return func(party *Party, player *Player, source *Card) bool {
+		revealing := min(count, len(player.MainDeck))
+		revealed := append([]*Card{}, player.MainDeck[:revealing]...)
+		party.reveal(player, revealed, true)
+		return len(revealed) > 0
+	}
+}
+
+// RevealEffect reveals the selected cards to every player.
+func RevealEffect(selector CardSelector) EffectAction {
+	return func(party *Party, player *Player, source *Card) bool {
+		done := false
+		for _, card := range selector(party, player, source) {
+			if owner := party.ownerOf(card); owner != nil {
+				party.reveal(owner, []*Card{card}, true)
+				done = true
+			}
 		}
+		return done
 	}
 }
 
-// RetireUnitEffect (Placeholder)
-func RetireUnitEffect() EffectAction {
-	return func(party *Party, player *Player, source *Card) {
-		fmt.Println("Effect: Retire Unit logic goes here")
+// ShuffleEffect shuffles the player's deck.
+func ShuffleEffect() EffectAction {
+	return func(party *Party, player *Player, source *Card) bool {
+		party.Shuffle(player)
+		return true
 	}
 }
@@ -0,0 +1,55 @@
+package core
+
+import "testing"
+
+// A thin deck limits one use of the effect, the next use looks at the full count again.
+func TestLookAtTopEffectKeepsCountBetweenUses(t *testing.T) {
+	party, _ := emptyBoard(t)
+	player := &party.Players[0]
+
+	var revealed []int
+	party.Reveal = func(viewerIndex int, ownerIndex int, cards []*Card) {
+		if viewerIndex == ownerIndex {
+			revealed = append(revealed, len(cards))
+		}
+	}
+
+	effect := RevealTopEffect(3)
+	deck := player.MainDeck
+	player.MainDeck = deck[:2]
+	effect(party, player, nil)
+	player.MainDeck = deck
+	effect(party, player, nil)
+
+	if len(revealed) != 2 || revealed[0] != 2 || revealed[1] != 3 {
+		t.Fatalf("revealed %v cards, want [2 3]", revealed)
+	}
+
+	lookAt := LookAtTopEffect(4, AnyCard(), 0, ZoneHand, RestBottom)
+	player.MainDeck = deck[:1]
+	lookAt(party, player, nil)
+	player.MainDeck = deck
+	revealed = nil
+	lookAt(party, player, nil)
+	if len(revealed) == 0 || revealed[0] != 4 {
+		t.Fatalf("second look revealed %v cards, want 4", revealed)
+	}
+}
+
+// Replacements created by an effect remember the card resolving it, as modifiers do.
+func TestReplacementEffectsKeepTheirSource(t *testing.T) {
+	party, card := emptyBoard(t)
+	player := &party.Players[0]
+
+	CannotBeRetiredEffect(ThisCard())(party, player, card)
+	PreventDamageEffect(1)(party, player, card)
+
+	if len(party.Replacements) != 2 {
+		t.Fatalf("%d replacements, want 2", len(party.Replacements))
+	}
+	for _, replacement := range party.Replacements {
+		if replacement.SourceID != card.ID {
+			t.Errorf("%s replacement has source %q, want %q", replacement.Action, replacement.SourceID, card.ID)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package core
+
+import (
+	"encoding/json"
+)
+
+// LogAction is the kind of state change a LogEntry records.
+type LogAction string
+
+const (
+	LogTurn        LogAction = "turn"
+	LogPhase       LogAction = "phase"
+	LogMove        LogAction = "move"
+	LogRide        LogAction = "ride"
+	LogCall        LogAction = "call"
+	LogAttack      LogAction = "attack"
+	LogBoost       LogAction = "boost"
+	LogStand       LogAction = "stand"
+	LogBattle      LogAction = "battle result"
+	LogFlip        LogAction = "flip"
+	LogLock        LogAction = "lock"
+	LogUnlock      LogAction = "unlock"
+	LogModifier    LogAction = "modifier"
+	LogEnergy      LogAction = "energy"
+	LogAbility     LogAction = "ability"
+	LogReplacement LogAction = "replacement"
+	LogChoice      LogAction = "choice"
+	LogReveal      LogAction = "reveal"
+	LogShuffle     LogAction = "shuffle"
+	LogRandom      LogAction = "random"
+	LogTurnOrder   LogAction = "turn order"
+	LogGameOver    LogAction = "game over"
+)
+
+// LogEntry is one state change of the game, in the order it happened. Player is -1 when
+// the change belongs to no player. Hidden entries show the cards to Player only, see VisibleTo.
+type LogEntry struct {
+	Seq      int        `json:"seq"`
+	Turn     int        `json:"turn"`
+	Phase    string     `json:"phase,omitempty"`
+	Player   int        `json:"player"`
+	Action   LogAction  `json:"action"`
+	Card     string     `json:"card,omitempty"`
+	CardName string     `json:"card_name,omitempty"`
+	Cards    []string   `json:"cards,omitempty"`
+	From     Zone       `json:"from,omitempty"`
+	To       Zone       `json:"to,omitempty"`
+	Circle   CircleID   `json:"circle,omitempty"`
+	Stat     Stat       `json:"stat,omitempty"`
+	Amount   int        `json:"amount,omitempty"`
+	Duration Duration   `json:"duration,omitempty"`
+	Choice   ChoiceKind `json:"choice,omitempty"`
+	// Values holds random results and chosen option indices
+	Values []int  `json:"values,omitempty"`
+	Detail string `json:"detail,omitempty"`
+	Hidden bool   `json:"hidden,omitempty"`
+}
+
+// VisibleTo returns the entry as the viewer may see it: the cards of a hidden entry
+// are removed for everyone but its player.
+func (entry LogEntry) VisibleTo(viewerIndex int) LogEntry {
+	if entry.Hidden && entry.Player != viewerIndex {
+		entry.Card = ""
+		entry.CardName = ""
+		entry.Cards = nil
+		entry.Values = nil
+	}
+	return entry
+}
+
+// HistoryJSON serializes the game log.
+func (party *Party) HistoryJSON() ([]byte, error) {
+	return json.MarshalIndent(party.History, "", "  ")
+}
+
+// log records the entry in History, numbered and stamped with the current turn and phase.
+func (party *Party) log(entry LogEntry) {
+	entry.Seq = len(party.History) + 1
+	entry.Turn = party.Turn
+	entry.Phase = party.CurrentPhase
+	party.History = append(party.History, entry)
+	if party.Logged != nil {
+		party.Logged(entry)
+	}
+}
+
+// logCard records an action of a card, Circle set when it is on the field.
+func (party *Party) logCard(action LogAction, player *Player, card *Card, detail string) {
+	entry := LogEntry{Action: action, Player: party.playerIndex(player), Detail: detail}
+	if card != nil {
+		entry.Card = card.ID
+		entry.CardName = card.Name
+		if player != nil {
+			if zone, id, _ := player.Locate(card); zone == ZoneField {
+				entry.Circle = id
+			}
+		}
+	}
+	party.log(entry)
+}
+
+// logMove records a card going from one zone to another, circle being the circle it
+// left or entered. Moves between hidden zones only show the card to its owner.
+func (party *Party) logMove(player *Player, card *Card, from Zone, to Zone, circle CircleID) {
+	party.log(LogEntry{
+		Action:   LogMove,
+		Player:   party.playerIndex(player),
+		Card:     card.ID,
+		CardName: card.Name,
+		From:     from,
+		To:       to,
+		Circle:   circle,
+		Hidden:   hiddenZone(from) && hiddenZone(to),
+	})
+}
+
+// hiddenZone tells whether the cards of a zone are unknown to the opponent.
+func hiddenZone(zone Zone) bool {
+	switch zone {
+	case ZoneHand, ZoneDeck, ZoneRideDeck, ZoneGDeck:
+		return true
+	}
+	return false
+}
+
+// cardIDs lists the IDs of the cards, for log entries.
+func cardIDs(cards []*Card) []string {
+	ids := make([]string, len(cards))
+	for i, card := range cards {
+		ids[i] = card.ID
+	}
+	return ids
+}
@@ -0,0 +1,109 @@
+package core
+
+// Stat is a card value that effects can modify.
+type Stat string
+
+const (
+	StatPower    Stat = "Power"
+	StatCritical Stat = "Critical"
+	StatShield   Stat = "Shield"
+	StatDrive    Stat = "Drive"
+)
+
+// Duration tells when a modifier expires.
+type Duration string
+
+const (
+	UntilEndOfBattle Duration = "until end of battle"
+	UntilEndOfTurn   Duration = "until end of turn"
+	// WhileOnField modifiers last until the card leaves the field.
+	WhileOnField Duration = "while on field"
+)
+
+// Modifier changes a stat of a card without touching the Card itself.
+type Modifier struct {
+	CardID   string
+	Stat     Stat
+	Amount   int
+	Duration Duration
+	SourceID string
+}
+
+// AddModifier gives card +amount (or -amount) to stat for the given duration.
+func (party *Party) AddModifier(card *Card, stat Stat, amount int, duration Duration, source *Card) {
+	if card == nil {
+		return
+	}
+	sourceID := ""
+	if source != nil {
+		sourceID = source.ID
+	}
+	party.Modifiers = append(party.Modifiers, Modifier{
+		CardID:   card.ID,
+		Stat:     stat,
+		Amount:   amount,
+		Duration: duration,
+		SourceID: sourceID,
+	})
+	entry := LogEntry{Action: LogModifier, Player: party.playerIndex(party.ownerOf(card)), Card: card.ID, CardName: card.Name, Stat: stat, Amount: amount, Duration: duration}
+	if source != nil {
+		entry.Detail = source.Name
+	}
+	party.log(entry)
+}
+
+// StatOf returns the printed value of stat plus every active modifier.
+func (party *Party) StatOf(card *Card, stat Stat) int {
+	if card == nil {
+		return 0
+	}
+
+	value := 0
+	switch stat {
+	case StatPower:
+		value = card.Power
+	case StatCritical:
+		value = card.Critical
+	case StatShield:
+		value = card.Shield
+	case StatDrive:
+		value = baseDriveCount(card)
+	}
+
+	for _, modifier := range party.Modifiers {
+		if modifier.CardID == card.ID && modifier.Stat == stat {
+			value += modifier.Amount
+		}
+	}
+	return value
+}
+
+// expireModifiers removes and logs every modifier with the given duration.
+func (party *Party) expireModifiers(duration Duration) {
+	kept := party.Modifiers[:0]
+	for _, modifier := range party.Modifiers {
+		if modifier.Duration != duration {
+			kept = append(kept, modifier)
+			continue
+		}
+		entry := LogEntry{Action: LogModifier, Player: -1, Card: modifier.CardID, Stat: modifier.Stat, Amount: -modifier.Amount, Duration: duration, Detail: "expired"}
+		if player, card := party.unitByID(modifier.CardID); card != nil {
+			entry.Player, entry.CardName = party.playerIndex(player), card.Name
+		}
+		party.log(entry)
+	}
+	party.Modifiers = kept
+}
+
+// unitByID finds the unit on a circle with the given ID, and its owner.
+func (party *Party) unitByID(id string) (*Player, *Card) {
+	for i := range party.Players {
+		player := &party.Players[i]
+		for _, circle := range AllCircles {
+			if card := player.Circle(circle).TopCard; card != nil && card.ID == id {
+				return player, card
+			}
+		}
+	}
+	return nil, nil
+}
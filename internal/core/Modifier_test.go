@@ -0,0 +1,20 @@
+package core
+
+import "testing"
+
+// Expiring modifiers are logged, so clients can undo them.
+func TestExpiredModifiersAreLogged(t *testing.T) {
+	party, card := emptyBoard(t)
+	party.AddModifier(card, StatPower, 5000, UntilEndOfTurn, nil)
+	party.AddModifier(card, StatPower, 2000, UntilEndOfBattle, nil)
+
+	party.expireModifiers(UntilEndOfTurn)
+
+	if len(party.Modifiers) != 1 || party.Modifiers[0].Duration != UntilEndOfBattle {
+		t.Fatalf("modifiers left: %+v", party.Modifiers)
+	}
+	last := party.History[len(party.History)-1]
+	if last.Action != LogModifier || last.Card != card.ID || last.Player != 0 || last.Amount != -5000 || last.Detail != "expired" {
+		t.Errorf("last log entry is %+v, want the expired modifier", last)
+	}
+}
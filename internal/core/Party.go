@@ -1,30 +1,48 @@
 package core
 
 import (
-	"bufio"
-	"encoding/json"
+	"errors"
+	"fmt"
 	"math/rand"
-	"os"
 	"strconv"
 	"strings"
 	"time"
 )
 
 type Event struct {
-	EventType string
-	Origin    string
-	FuncCall  func()
+	EventType string `json:"event_type"`
+	Origin    string `json:"origin"`
+	// PlayerIndex is the player the event belongs to, who resolves it
+	PlayerIndex int    `json:"player"`
+	Description string `json:"description"`
+	FuncCall    func() `json:"-"`
 }
 
 type Deck struct {
-	RideDeck [5]*Card
-	MainDeck [50]*Card
-	GDeck    [8]*Card
+	RideDeck []*Card
+	MainDeck []*Card
+	GDeck    []*Card
+}
+
+// Clone copies the deck with new card instances, so one list can feed several parties.
+func (deck *Deck) Clone() *Deck {
+	clone := func(cards []*Card) []*Card {
+		result := make([]*Card, len(cards))
+		for i, card := range cards {
+			result[i] = card.Clone()
+		}
+		return result
+	}
+	return &Deck{
+		RideDeck: clone(deck.RideDeck),
+		MainDeck: clone(deck.MainDeck),
+		GDeck:    clone(deck.GDeck),
+	}
 }
 
 type Circle struct {
 	TopCard *Card
-	Soul    *Card
+	Soul    []*Card
 	Boon    *Card
 }
 
@@ -39,6 +57,8 @@ type Player struct {
 	TriggerZone []*Card
 	BindZone    []*Card
 	DropZone    []*Card
+	CrestZone   []*Card
+	Energy      int
 	Rear1       Circle
 	Vanguard    Circle
 	Rear2       Circle
@@ -47,6 +67,94 @@ type Player struct {
 	Rear5       Circle
 }
 
+// CircleID names a circle: front row R1 VC R2, back row R3 R4 R5 behind them.
+type CircleID string
+
+const (
+	CircleRear1    CircleID = "R1"
+	CircleVanguard CircleID = "VC"
+	CircleRear2    CircleID = "R2"
+	CircleRear3    CircleID = "R3"
+	CircleRear4    CircleID = "R4"
+	CircleRear5    CircleID = "R5"
+)
+
+var (
+	AllCircles = []CircleID{CircleRear1, CircleVanguard, CircleRear2, CircleRear3, CircleRear4, CircleRear5}
+	FrontRow   = []CircleID{CircleRear1, CircleVanguard, CircleRear2}
+	BackRow    = []CircleID{CircleRear3, CircleRear4, CircleRear5}
+)
+
+// Column returns the column of a circle: 0 left (R1, R3), 1 center (VC, R4), 2 right (R2, R5).
+func Column(id CircleID) int {
+	for i := range FrontRow {
+		if FrontRow[i] == id || BackRow[i] == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func IsFrontRow(id CircleID) bool {
+	return Column(id) != -1 && FrontRow[Column(id)] == id
+}
+
+// BackRowOf returns the circle behind a front row circle.
+func BackRowOf(id CircleID) (CircleID, bool) {
+	if !IsFrontRow(id) {
+		return "", false
+	}
+	return BackRow[Column(id)], true
+}
+
+// FrontRowOf returns the circle in front of a back row circle.
+func FrontRowOf(id CircleID) (CircleID, bool) {
+	column := Column(id)
+	if column == -1 || IsFrontRow(id) {
+		return "", false
+	}
+	return FrontRow[column], true
+}
+
+func (player *Player) Circle(id CircleID) *Circle {
+	switch id {
+	case CircleRear1:
+		return &player.Rear1
+	case CircleVanguard:
+		return &player.Vanguard
+	case CircleRear2:
+		return &player.Rear2
+	case CircleRear3:
+		return &player.Rear3
+	case CircleRear4:
+		return &player.Rear4
+	case CircleRear5:
+		return &player.Rear5
+	}
+	return nil
+}
+
+// CircleOf finds the circle the card stands on.
+func (player *Player) CircleOf(card *Card) (CircleID, bool) {
+	for _, id := range AllCircles {
+		if card != nil && player.Circle(id).TopCard == card {
+			return id, true
+		}
+	}
+	return "", false
+}
+
+// OccupiedCircles filters the given circles down to those holding a unit.
+func (player *Player) OccupiedCircles(circles []CircleID) []CircleID {
+	result := []CircleID{}
+	for _, id := range circles {
+		if player.Circle(id).TopCard != nil {
+			result = append(result, id)
+		}
+	}
+	return result
+}
+
 const (
 	PhaseStand  = "Stand Phase"
 	PhaseDraw   = "Draw Phase"
@@ -63,70 +171,290 @@ type Party struct {
 	Turn         int
 	CurrentPhase string
 	EventQueue   []Event
-	History      []Event
+	History      []LogEntry
+	Modifiers    []Modifier
+	Replacements []Replacement
+	Battle       *Battle
+	Rules        Rules
+	// TurnCounters holds once-per-turn usage counts, cleared at end of turn
+	TurnCounters map[string]int
+	// FightCounters holds once-per-fight usage counts, never cleared
+	FightCounters map[string]int
+	Over          bool
+	// Winner is -1 while the game goes on and when it ends in a draw
+	Winner int
+	// EndReason tells why the game ended
+	EndReason string
+	// Decide asks a player to make a choice and returns the chosen option indices.
+	// When nil, the first Min options are always taken.
+	Decide func(playerIndex int, choice Choice) []int
+	// Reveal shows cards of the owner to the viewer, called for each player allowed to see them.
+	Reveal func(viewerIndex int, ownerIndex int, cards []*Card)
+	// Logged is called with each entry added to History.
+	Logged func(entry LogEntry)
+	// Decisions are the answers of the players in order, see Record and Replay
+	Decisions []Decision
+	decklists []DeckListJSON
+	replay    *replayer
+	// resolving is set while resolveEvents runs, new triggers then wait their turn
+	resolving bool
+	// replacementSequence numbers replacements in the order they were added
+	replacementSequence int
+}
+
+func (party *Party) playerIndex(player *Player) int {
+	for i := range party.Players {
+		if &party.Players[i] == player {
+			return i
+		}
+	}
+	return -1
+}
+
+// opponent returns the other player of a two player party.
+func (party *Party) opponent(player *Player) *Player {
+	index := party.playerIndex(player)
+	if index == -1 || len(party.Players) < 2 {
+		return nil
+	}
+	return &party.Players[(index+1)%len(party.Players)]
 }
 
+// checkEffects triggers the AUTO abilities waiting for a timing that is not about a specific card.
 func (party *Party) checkEffects(trigger string) {
-	// Placeholder: In the future, iterate through cards/effects to see if any trigger matches
-	// fmt.Println("Checking effects for:", trigger)
+	party.emit(trigger, nil)
 }
 
 // ProcessPhase executes the standard flow of a phase: Start Effects -> Action -> End Effects
 func (party *Party) ProcessPhase(phaseName string, defaultAction func()) {
 	party.CurrentPhase = phaseName
 	// println("Processing " + phaseName)
+	party.log(LogEntry{Action: LogPhase, Player: party.playerIndex(party.turnPlayer()), Detail: phaseName})
 
 	// 1. Start of Phase Effects
-	party.checkEffects("START_" + strings.ToUpper(strings.ReplaceAll(phaseName, " ", "_")))
+	party.checkEffects(PhaseTiming("START_", phaseName))
 
-	// 2. Action
-	// In a full implementation, we would check if an effect REPLACES the default action here.
-	if defaultAction != nil {
+	// 2. Action, unless an effect replaces it
+	if defaultAction != nil && party.replace(&PendingAction{Action: ActionPhase, Player: party.turnPlayer(), Phase: phaseName}) {
 		defaultAction()
 	}
 
 	// 3. End of Phase Effects
-	party.checkEffects("END_" + strings.ToUpper(strings.ReplaceAll(phaseName, " ", "_")))
+	party.checkEffects(PhaseTiming("END_", phaseName))
+}
+
+// PhaseTiming is the timing emitted at the start or end of a phase, e.g. "START_RIDE_PHASE".
+func PhaseTiming(prefix string, phaseName string) string {
+	return prefix + strings.ToUpper(strings.ReplaceAll(phaseName, " ", "_"))
+}
+
+// MaxDamage is the damage count at which a player loses.
+const MaxDamage = 6
+
+// TimingEndOfTurn is emitted by the End Phase for "at the end of turn" abilities.
+const TimingEndOfTurn = "END_OF_TURN"
+
+// Run plays turns, handing over to the next player after each End Phase, until the game is over.
+func (party *Party) Run() {
+	for !party.Over {
+		party.StartTurn()
+	}
+	println("Game over, winner: Player", party.Winner)
+}
+
+// ruleCheck ends the game when a player has 6 damage or no card left in deck.
+// Every player is checked, the game is a draw when they lose at the same time.
+func (party *Party) ruleCheck() bool {
+	if party.Over {
+		return true
+	}
+	losers := []int{}
+	reasons := []string{}
+	for i := range party.Players {
+		player := &party.Players[i]
+		switch {
+		case len(player.DamageZone) >= MaxDamage:
+			losers = append(losers, i)
+			reasons = append(reasons, "Player "+strconv.Itoa(i)+" loses: damage")
+		case len(player.MainDeck) == 0:
+			losers = append(losers, i)
+			reasons = append(reasons, "Player "+strconv.Itoa(i)+" loses: deck out")
+		}
+	}
+	if len(losers) == 0 {
+		return false
+	}
+
+	party.Over = true
+	party.Winner = -1
+	if len(losers) == 1 {
+		party.Winner = (losers[0] + 1) % len(party.Players)
+		party.EndReason = reasons[0]
+	} else {
+		party.EndReason = "draw: " + strings.Join(reasons, ", ")
+	}
+	println(party.EndReason)
+	party.log(LogEntry{Action: LogGameOver, Player: party.Winner, Detail: party.EndReason})
+	return true
 }
 
 // StartTurn executes the phases for the current turn's player
 func (party *Party) StartTurn() {
+	if party.Turn == 0 {
+		party.startGame()
+	}
 	party.Turn++
 
 	player := &party.Players[(party.Turn-1)%len(party.Players)]
 	println("Turn", party.Turn, "starts for Player", (party.Turn-1)%len(party.Players))
+	party.CurrentPhase = ""
+	party.log(LogEntry{Action: LogTurn, Player: (party.Turn - 1) % len(party.Players)})
+
+	phases := []func(*Player){
+		party.StandPhase,
+		func(player *Player) {
+			if party.isFirstTurn() && party.Rules.SkipFirstDraw {
+				println("First turn: draw skipped")
+				return
+			}
+			party.DrawPhase(player)
+		},
+		party.RidePhase,
+		party.MainPhase,
+		func(player *Player) {
+			if party.isFirstTurn() && party.Rules.NoFirstTurnBattle {
+				println("First turn: no battle phase")
+				return
+			}
+			party.BattlePhase(player)
+		},
+		party.EndPhase,
+	}
 
-	party.StandPhase(player)
-	party.DrawPhase(player)
-	party.RidePhase(player)
-	party.MainPhase(player)
-	party.BattlePhase(player)
-	party.EndPhase(player)
+	for _, phase := range phases {
+		phase(player)
+		if party.ruleCheck() {
+			return
+		}
+	}
 }
 
 func (party *Party) StandPhase(player *Player) {
 	party.ProcessPhase(PhaseStand, func() {
 		// Stand all units
-		// Placeholder: iterate over circles and set IsStand = true (need to add IsStand to Card or Circle)
-		// For now, just logging
-		// println("Standing units...")
+		for _, id := range AllCircles {
+			if card := player.Circle(id).TopCard; card != nil && card.Rested {
+				card.Rested = false
+				party.logCard(LogStand, player, card, "")
+			}
+		}
 	})
 }
 
 func (party *Party) DrawPhase(player *Player) {
 	party.ProcessPhase(PhaseDraw, func() {
 		// Standard Draw: Draw 1 card
-		// Effects could replace this (e.g., skip draw to do X) if implemented in checkEffects/middleware
-		draw(player, 1)
+		party.Draw(player, 1)
 	})
 }
 
 func (party *Party) RidePhase(player *Player) {
 	party.ProcessPhase(PhaseRide, func() {
-		// Logic to wait for user input for Ride would go here
+		// Ride is optional: choose a card of the ride deck or the hand, or none
+		candidates := party.rideCandidates(player)
+		if len(candidates) == 0 {
+			return
+		}
+		chosen := party.choose(player, Choice{Kind: ChoiceRide, Prompt: "Choose a card to ride", Options: cardOptions(candidates), Min: 0, Max: 1})
+		if len(chosen) == 1 {
+			if err := party.Ride(player, candidates[chosen[0]]); err != nil {
+				fmt.Printf("Ride: %s\n", err)
+			}
+		}
 	})
 }
 
+// rideCandidates lists the cards of the ride deck and the hand the player can ride.
+func (party *Party) rideCandidates(player *Player) []*Card {
+	candidates := []*Card{}
+	for _, card := range append(append([]*Card{}, player.RideDeck...), player.Hand...) {
+		if canRide(player, card) && (!containsCard(player.RideDeck, card) || len(player.Hand) > 0) {
+			candidates = append(candidates, card)
+		}
+	}
+	return candidates
+}
+
+// canRide checks the grade: a unit of the vanguard's grade or one grade higher.
+func canRide(player *Player, card *Card) bool {
+	vanguard := player.Vanguard.TopCard
+	if vanguard == nil || card.Grade == NoGrade || !card.CardType.IsUnit() {
+		return false
+	}
+	return card.Grade == vanguard.Grade || card.Grade == vanguard.Grade+1
+}
+
+// Ride puts a card of the hand or the ride deck on the vanguard circle, the previous
+// vanguard going into the soul. Riding from the ride deck costs discarding a card.
+func (party *Party) Ride(player *Player, card *Card) error {
+	if _, err := checkRide(player, card); err != nil {
+		return err
+	}
+
+	action := &PendingAction{Action: ActionRide, Player: player, Card: card}
+	if !party.replace(action) {
+		return nil
+	}
+	// A replacement may have changed the card, the cost depends on where the ridden card is
+	fromRideDeck, err := checkRide(player, action.Card)
+	if err != nil {
+		return err
+	}
+
+	if fromRideDeck {
+		hand := append([]*Card{}, player.Hand...)
+		chosen := party.choose(player, Choice{Kind: ChoiceDiscard, Prompt: "Discard a card to ride from the ride deck", Options: cardOptions(hand), Min: 1, Max: 1})
+		discarded := hand[chosen[0]]
+		removeFromZone(&player.Hand, discarded)
+		player.DropZone = append(player.DropZone, discarded)
+		party.logMove(player, discarded, ZoneHand, ZoneDrop, "")
+		party.emit(TimingPutIntoDropZone, discarded)
+	}
+
+	previous := player.Vanguard.TopCard
+	_, from, _ := party.takeCard(action.Card)
+	if previous != nil {
+		party.leaveField(previous)
+		previous.Rested = false
+		player.Vanguard.Soul = append(player.Vanguard.Soul, previous)
+		party.logMove(player, previous, ZoneField, ZoneSoul, CircleVanguard)
+	}
+	action.Card.Rested = false
+	player.Vanguard.TopCard = action.Card
+	fmt.Printf("Ride: %s\n", action.Card.Name)
+	party.log(LogEntry{Action: LogRide, Player: party.playerIndex(player), Card: action.Card.ID, CardName: action.Card.Name, From: from, To: ZoneField, Circle: CircleVanguard})
+	party.emit(TimingRide, action.Card)
+	party.emit(TimingPlaced, action.Card)
+	if previous != nil {
+		party.emit(TimingPutIntoSoul, previous)
+	}
+	return nil
+}
+
+// checkRide tells whether the player may ride the card, and whether it comes from the ride deck.
+func checkRide(player *Player, card *Card) (bool, error) {
+	fromRideDeck := containsCard(player.RideDeck, card)
+	switch {
+	case !fromRideDeck && !containsCard(player.Hand, card):
+		return false, errors.New(card.Name + " is not in the hand nor the ride deck")
+	case !canRide(player, card):
+		return false, errors.New("cannot ride " + card.Name + " on the current vanguard")
+	case fromRideDeck && len(player.Hand) == 0:
+		return false, errors.New("riding from the ride deck needs a card to discard")
+	}
+	return fromRideDeck, nil
+}
+
 func (party *Party) MainPhase(player *Player) {
 	party.ProcessPhase(PhaseMain, func() {
 		// Main phase actions
@@ -135,16 +463,40 @@ func (party *Party) MainPhase(player *Player) {
 
 func (party *Party) BattlePhase(player *Player) {
 	party.ProcessPhase(PhaseBattle, func() {
-		// Battle phase actions
+		// Attack until the player declares no more attacks
+		for !party.ruleCheck() && party.battle(player) {
+		}
 	})
 }
 
 func (party *Party) EndPhase(player *Player) {
 	party.ProcessPhase(PhaseEnd, func() {
 		// End of turn effects
+		party.checkEffects(TimingEndOfTurn)
+		party.expireModifiers(UntilEndOfTurn)
+		party.expireReplacements(UntilEndOfTurn)
+		party.TurnCounters = map[string]int{}
+		party.unlockUnits(player)
+		party.enforceHandLimit(player)
 	})
 }
 
+// enforceHandLimit makes the player discard down to Rules.HandLimit, if any.
+func (party *Party) enforceHandLimit(player *Player) {
+	excess := len(player.Hand) - party.Rules.HandLimit
+	if party.Rules.HandLimit <= 0 || excess <= 0 {
+		return
+	}
+
+	hand := append([]*Card{}, player.Hand...)
+	chosen := party.choose(player, Choice{Kind: ChoiceDiscard, Prompt: "Hand limit: discard " + strconv.Itoa(excess) + " card(s)", Options: cardOptions(hand), Min: excess, Max: excess})
+	for _, index := range chosen {
+		removeFromZone(&player.Hand, hand[index])
+		player.DropZone = append(player.DropZone, hand[index])
+		party.logMove(player, hand[index], ZoneHand, ZoneDrop, "")
+	}
+}
+
 func PrintDeck(deck *Deck) {
 	println("Ride Deck: [")
 	for _, card := range deck.RideDeck {
@@ -236,24 +588,11 @@ func PrintParty(party *Party) {
 	}
 }
 
-func findCardByNumber(cards []RawCard, targetNumber string) *RawCard {
-	// println("targetNumber: " + targetNumber)
-	for i := range cards {
-		if cards[i].CardNumberFull == targetNumber {
-			// On retourne un pointeur vers l'élément trouvé
-			// println(cards[i].CardNumberFull)
-			return &cards[i]
-		}
-	}
-	// Si rien n'est trouvé, on retourne nil
-	return nil
-}
-
 func DeckToPlayer(deck Deck) Player {
 	return Player{
-		RideDeck:    deck.RideDeck[:],
-		MainDeck:    deck.MainDeck[:],
-		GDeck:       deck.GDeck[:],
+		RideDeck:    append([]*Card{}, deck.RideDeck...),
+		MainDeck:    append([]*Card{}, deck.MainDeck...),
+		GDeck:       append([]*Card{}, deck.GDeck...),
 		DamageZone:  []*Card{},
 		Hand:        []*Card{},
 		OrderZone:   []*Card{},
@@ -261,6 +600,7 @@ func DeckToPlayer(deck Deck) Player {
 		TriggerZone: []*Card{},
 		BindZone:    []*Card{},
 		DropZone:    []*Card{},
+		CrestZone:   []*Card{},
 		Rear1:       Circle{},
 		Vanguard:    Circle{},
 		Rear2:       Circle{},
@@ -270,144 +610,38 @@ func DeckToPlayer(deck Deck) Player {
 	}
 }
 
-func ParseDeckFile(filePath string) (*Deck, error) {
-	file, err := os.Open(filePath)
-	if err != nil {
-		return nil, err
-	}
-	defer file.Close()
-
-	RideDeck := [][]string{}
-	MainDeck := [][]string{}
-	GDeck := [][]string{}
-
-	scanner := bufio.NewScanner(file)
-	currentSection := ""
-
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-
-		// Ignorer les lignes vides
-		if line == "" {
-			continue
-		}
-
-		// Détection de la section
-		if strings.HasPrefix(line, "#") {
-			currentSection = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(line, "#")))
-			continue
-		}
-
-		// Parsing de la ligne de carte
-		cardData := parseCardLine(line)
-
-		// Ajout à la bonne section
-		switch currentSection {
-		case "ride":
-			RideDeck = append(RideDeck, cardData)
-		case "main":
-			MainDeck = append(MainDeck, cardData)
-		case "g":
-			GDeck = append(GDeck, cardData)
-		}
-	}
-
-	databaseFile, err := os.Open("vg_parsed_cards.json")
-	if err != nil {
-		return nil, err
-	}
-	defer databaseFile.Close()
-
-	var database []RawCard
-	if err := json.NewDecoder(databaseFile).Decode(&database); err != nil {
-		return nil, err
-	}
-
-	deck := &Deck{}
-
-	// Helper function to process deck sections
-	processSection := func(sectionData [][]string, targetArray []*Card) ([]*Card, error) {
-		result := []*Card{}
-		for _, cardData := range sectionData {
-			// Remove 'x' from count (e.g., "4x" -> "4")
-			countStr := strings.TrimSuffix(cardData[0], "x")
-			count, err := strconv.Atoi(countStr)
-			if err != nil {
-				return nil, err
-			}
-			cardNumber := cardData[3]
-
-			rawCard := findCardByNumber(database, cardNumber)
-			var card *Card
-			if rawCard != nil {
-				card, err = rawCard.ToCard()
-				if err != nil {
-					return nil, err
-				}
-			} else {
-				// Keep invalid/not found cards as nil
-				card = nil
-			}
-
-			for i := 0; i < count; i++ {
-				result = append(result, card)
-			}
-		}
-		return result, nil
-	}
-
-	// Process Ride Deck
-	rideCards, err := processSection(RideDeck, deck.RideDeck[:0])
-	if err != nil {
-		return nil, err
-	}
-	copy(deck.RideDeck[:], rideCards)
-
-	// Process Main Deck
-	mainCards, err := processSection(MainDeck, deck.MainDeck[:0])
-	if err != nil {
-		return nil, err
-	}
-	copy(deck.MainDeck[:], mainCards)
-
-	// Process G Deck
-	gCards, err := processSection(GDeck, deck.GDeck[:0])
-	if err != nil {
-		return nil, err
-	}
-	copy(deck.GDeck[:], gCards)
-
-	return deck, nil
-}
-
-// parseCardLine découpe la ligne intelligemment
-func parseCardLine(line string) []string {
-	// 1. Extraire la quantité (ex: 1x)
-	return strings.Split(line, "\t")
-}
-
 func InitParty(decks []*Deck) *Party {
 	var players []Player
+	decklists := []DeckListJSON{}
 
 	for _, deck := range decks {
 		if deck != nil {
 			players = append(players, DeckToPlayer(*deck))
+			decklists = append(decklists, orderedDeckList(deck))
 		}
 	}
 
 	return &Party{
-		Players:    players,
-		Turn:       0,
-		EventQueue: []Event{},
-		History:    []Event{},
+		Players:       players,
+		Turn:          0,
+		Rules:         DefaultRules(),
+		Winner:        -1,
+		TurnCounters:  map[string]int{},
+		FightCounters: map[string]int{},
+		EventQueue:    []Event{},
+		History:       []LogEntry{},
+		Decisions:     []Decision{},
+		decklists:     decklists,
 	}
 }
 
-func draw(player *Player, count int) bool {
+func (party *Party) draw(player *Player, count int) bool {
 	if len(player.MainDeck) >= count {
 		for i := 0; i < count; i++ {
-			player.Hand = append(player.Hand, player.MainDeck[0])
+			card := player.MainDeck[0]
+			player.Hand = append(player.Hand, card)
 			player.MainDeck = player.MainDeck[1:]
+			party.logMove(player, card, ZoneDeck, ZoneHand, "")
 		}
 		return true
 	}
@@ -428,21 +662,29 @@ func InitGame(party *Party, seed string) {
 	for i := range party.Players {
 		player := &party.Players[i]
 
-		party.rand.Shuffle(len(player.MainDeck), func(i, j int) {
-			player.MainDeck[i], player.MainDeck[j] = player.MainDeck[j], player.MainDeck[i]
-		})
+		party.Shuffle(player)
 
 		for j, card := range player.RideDeck {
 			if card != nil && card.Grade == 0 {
 				player.Vanguard.TopCard = card
 				println("Vanguard : " + ToString(card))
-				card.Locked = true
 				player.RideDeck = append(player.RideDeck[:j], player.RideDeck[j+1:]...)
+				party.logMove(player, card, ZoneRideDeck, ZoneField, CircleVanguard)
 				break
 			}
 		}
 
-		draw(player, 5)
+		// Crests start the game in the crest zone
+		for _, card := range append([]*Card{}, player.RideDeck...) {
+			if card != nil && card.CardType == CardTypeCrest {
+				removeFromZone(&player.RideDeck, card)
+				player.CrestZone = append(player.CrestZone, card)
+				println("Crest : " + ToString(card))
+				party.logMove(player, card, ZoneRideDeck, ZoneCrest, "")
+			}
+		}
+
+		party.draw(player, 5)
 	}
 }
 
@@ -453,6 +695,7 @@ func (party *Party) DecideTurnOrder(onRoll func(int, int), askChoice func(winner
 		r0 := party.rand.Intn(6) + 1
 		r1 := party.rand.Intn(6) + 1
 
+		party.log(LogEntry{Action: LogRandom, Player: -1, Values: []int{r0, r1}, Detail: "dice"})
 		onRoll(r0, r1)
 
 		if r0 != r1 {
@@ -461,6 +704,12 @@ func (party *Party) DecideTurnOrder(onRoll func(int, int), askChoice func(winner
 				winner = 1
 			}
 			choice := askChoice(winner)
+			answer := []int{0}
+			if choice == "second" {
+				answer = []int{1}
+			}
+			party.recordDecision(winner, ChoiceTurnOrder, answer)
+			party.log(LogEntry{Action: LogTurnOrder, Player: winner, Detail: choice})
 			// If winner chooses second, swap
 			// Default winner is P0 (index winner)
 			// If P0 wins and chooses Second -> Swap
@@ -478,7 +727,6 @@ func (party *Party) DecideTurnOrder(onRoll func(int, int), askChoice func(winner
 			}
 			return doSwap
 		}
-		time.Sleep(1 * time.Second)
 	}
 }
 
@@ -518,6 +766,7 @@ func (party *Party) PerformMulligan(requestMulligan func(playerIndex int, hand [
 				validIndices = append(validIndices, idx)
 			}
 		}
+		party.recordDecision(i, ChoiceMulligan, validIndices)
 
 		if len(validIndices) > 0 {
 			cardsToDiscard := []*Card{}
@@ -540,12 +789,13 @@ func (party *Party) PerformMulligan(requestMulligan func(playerIndex int, hand [
 
 			player.Hand = newHand
 			player.MainDeck = append(player.MainDeck, tempDeck...)
+			for _, card := range tempDeck {
+				party.logMove(player, card, ZoneHand, ZoneDeck, "")
+			}
 
-			party.rand.Shuffle(len(player.MainDeck), func(i, j int) {
-				player.MainDeck[i], player.MainDeck[j] = player.MainDeck[j], player.MainDeck[i]
-			})
+			party.Shuffle(player)
 
-			draw(player, len(cardsToDiscard))
+			party.draw(player, len(cardsToDiscard))
 		}
 	}
 }
@@ -0,0 +1,35 @@
+package core
+
+import "testing"
+
+func TestRuleCheck(t *testing.T) {
+	cases := []struct {
+		name   string
+		damage [2]int
+		deck   [2]int
+		over   bool
+		winner int
+	}{
+		{"nobody lost", [2]int{5, 5}, [2]int{1, 1}, false, -1},
+		{"player 0 damage", [2]int{6, 5}, [2]int{10, 10}, true, 1},
+		{"player 1 deck out", [2]int{0, 0}, [2]int{10, 0}, true, 0},
+		{"both damage", [2]int{6, 6}, [2]int{10, 10}, true, -1},
+		{"damage and deck out", [2]int{6, 0}, [2]int{10, 0}, true, -1},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			party, _ := emptyBoard(t)
+			for i := range party.Players {
+				party.Players[i].DamageZone = testCards(c.damage[i])
+				party.Players[i].MainDeck = testCards(c.deck[i])
+			}
+
+			if got := party.ruleCheck(); got != c.over || party.Over != c.over || party.Winner != c.winner {
+				t.Errorf("got over %t, winner %d; want %t, %d", party.Over, party.Winner, c.over, c.winner)
+			}
+			if c.over && party.History[len(party.History)-1].Action != LogGameOver {
+				t.Error("game over not logged")
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ReplaceableAction names a game action replacement effects can intercept.
+type ReplaceableAction string
+
+const (
+	ActionDraw   ReplaceableAction = "draw"
+	ActionRetire ReplaceableAction = "retire"
+	ActionDamage ReplaceableAction = "damage"
+	ActionRide   ReplaceableAction = "ride"
+	// ActionPhase is the default action of a phase, e.g. the draw of the Draw Phase
+	ActionPhase ReplaceableAction = "phase"
+)
+
+// PendingAction is an action about to happen. Replacements may change Count or Card,
+// or cancel it, doing something else instead.
+type PendingAction struct {
+	Action    ReplaceableAction
+	Player    *Player
+	Card      *Card
+	Count     int
+	Phase     string
+	Cancelled bool
+}
+
+// Replacement is an "instead of ..." or "... cannot ..." effect. Applies selects the actions
+// it intercepts, Replace modifies or cancels them. Each replacement applies at most once per action.
+type Replacement struct {
+	Action   ReplaceableAction
+	Duration Duration
+	// SourceID is the card creating the replacement, WhileOnField replacements end when it leaves the field
+	SourceID string
+	// Priority orders replacements applying to the same action, highest first,
+	// then in the order they were added
+	Priority int
+	// Once replacements are removed after applying ("the next time ...")
+	Once    bool
+	Applies func(party *Party, action *PendingAction) bool
+	Replace func(party *Party, action *PendingAction)
+
+	sequence int
+}
+
+// AddReplacement registers a replacement effect.
+func (party *Party) AddReplacement(replacement Replacement) {
+	party.replacementSequence++
+	replacement.sequence = party.replacementSequence
+	party.Replacements = append(party.Replacements, replacement)
+}
+
+// replace runs the applicable replacements on the action, and reports whether it still happens.
+func (party *Party) replace(action *PendingAction) bool {
+	applicable := []Replacement{}
+	for _, replacement := range party.Replacements {
+		if replacement.Action == action.Action && (replacement.Applies == nil || replacement.Applies(party, action)) {
+			applicable = append(applicable, replacement)
+		}
+	}
+	sort.SliceStable(applicable, func(i, j int) bool {
+		if applicable[i].Priority != applicable[j].Priority {
+			return applicable[i].Priority > applicable[j].Priority
+		}
+		return applicable[i].sequence < applicable[j].sequence
+	})
+
+	for _, replacement := range applicable {
+		if action.Cancelled {
+			break
+		}
+		// An earlier replacement may have changed the action
+		if replacement.Applies != nil && !replacement.Applies(party, action) {
+			continue
+		}
+		fmt.Printf("Replacement: %s\n", action.Action)
+		entry := LogEntry{Action: LogReplacement, Player: party.playerIndex(action.Player), Detail: string(action.Action)}
+		if action.Card != nil {
+			entry.Card, entry.CardName = action.Card.ID, action.Card.Name
+		}
+		party.log(entry)
+		if replacement.Once {
+			party.removeReplacement(replacement.sequence)
+		}
+		if replacement.Replace != nil {
+			replacement.Replace(party, action)
+		}
+	}
+	return !action.Cancelled
+}
+
+func (party *Party) removeReplacement(sequence int) {
+	kept := party.Replacements[:0]
+	for _, replacement := range party.Replacements {
+		if replacement.sequence != sequence {
+			kept = append(kept, replacement)
+		}
+	}
+	party.Replacements = kept
+}
+
+// expireReplacements removes every replacement with the given duration.
+func (party *Party) expireReplacements(duration Duration) {
+	kept := party.Replacements[:0]
+	for _, replacement := range party.Replacements {
+		if replacement.Duration != duration {
+			kept = append(kept, replacement)
+		}
+	}
+	party.Replacements = kept
+}
+
+// removeSourceReplacements ends the WhileOnField replacements of a card leaving the field.
+func (party *Party) removeSourceReplacements(card *Card) {
+	kept := party.Replacements[:0]
+	for _, replacement := range party.Replacements {
+		if replacement.Duration != WhileOnField || replacement.SourceID != card.ID {
+			kept = append(kept, replacement)
+		}
+	}
+	party.Replacements = kept
+}
+
+// Draw makes the player draw, unless a replacement changes or prevents it.
+func (party *Party) Draw(player *Player, count int) bool {
+	action := &PendingAction{Action: ActionDraw, Player: player, Count: count}
+	if !party.replace(action) {
+		return false
+	}
+	return party.draw(action.Player, action.Count)
+}
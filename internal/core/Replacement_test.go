@@ -0,0 +1,190 @@
+package core
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDrawReplacement(t *testing.T) {
+	cases := []struct {
+		name    string
+		replace func(party *Party, action *PendingAction)
+		drawn   int
+	}{
+		{"none", nil, 1},
+		{"one more", func(party *Party, action *PendingAction) { action.Count++ }, 2},
+		{"cancelled", func(party *Party, action *PendingAction) { action.Cancelled = true }, 0},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			party, _ := emptyBoard(t)
+			player := &party.Players[0]
+			if c.replace != nil {
+				party.AddReplacement(Replacement{Action: ActionDraw, Duration: UntilEndOfTurn, Replace: c.replace})
+			}
+			hand := len(player.Hand)
+
+			party.Draw(player, 1)
+
+			if len(player.Hand) != hand+c.drawn {
+				t.Errorf("drew %d card(s), want %d", len(player.Hand)-hand, c.drawn)
+			}
+		})
+	}
+}
+
+func TestRetireReplacement(t *testing.T) {
+	party, _ := emptyBoard(t)
+	player := &party.Players[0]
+	protected, other := player.Rear1.TopCard, player.Rear4.TopCard
+	party.AddReplacement(Replacement{
+		Action:   ActionRetire,
+		Duration: UntilEndOfTurn,
+		Applies:  func(party *Party, action *PendingAction) bool { return action.Card == protected },
+		Replace:  func(party *Party, action *PendingAction) { action.Cancelled = true },
+	})
+
+	if party.Retire(protected) || player.Rear1.TopCard != protected {
+		t.Error("protected rear-guard retired")
+	}
+	if !party.Retire(other) || player.Rear4.TopCard != nil {
+		t.Error("other rear-guard not retired")
+	}
+}
+
+// Player 1's vanguard hits player 0's vanguard for 1 damage, changed by the replacement.
+func TestDamageReplacement(t *testing.T) {
+	cases := []struct {
+		name    string
+		replace func(party *Party, action *PendingAction)
+		damage  int
+	}{
+		{"none", nil, 1},
+		{"one more", func(party *Party, action *PendingAction) { action.Count++ }, 2},
+		{"prevented", func(party *Party, action *PendingAction) { action.Count--; action.Cancelled = action.Count == 0 }, 0},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			party, _ := emptyBoard(t)
+			attacker, defender := &party.Players[1], &party.Players[0]
+			party.Battle = &Battle{
+				Attacker:       attacker.Vanguard.TopCard,
+				AttackerCircle: CircleVanguard,
+				Target:         defender.Vanguard.TopCard,
+				TargetCircle:   CircleVanguard,
+				CannotBeHit:    map[string]bool{},
+			}
+			if c.replace != nil {
+				party.AddReplacement(Replacement{Action: ActionDamage, Duration: UntilEndOfTurn, Replace: c.replace})
+			}
+			damage := len(defender.DamageZone)
+
+			party.damageStep(attacker, defender)
+
+			if len(defender.DamageZone) != damage+c.damage {
+				t.Errorf("took %d damage, want %d", len(defender.DamageZone)-damage, c.damage)
+			}
+		})
+	}
+}
+
+// A replacement changing the ridden card to one of the ride deck makes the ride cost a discard.
+func TestRideReplacementChangesTheCard(t *testing.T) {
+	party, _ := emptyBoard(t)
+	player := &party.Players[0]
+	fromHand := player.Hand[3]
+	fromRideDeck := &Card{Name: "Ride Deck Unit", Grade: 3, CardType: CardTypeNormalUnit}
+	player.RideDeck = []*Card{fromRideDeck}
+	party.AddReplacement(Replacement{
+		Action:   ActionRide,
+		Duration: UntilEndOfTurn,
+		Replace:  func(party *Party, action *PendingAction) { action.Card = fromRideDeck },
+	})
+	hand, drop := len(player.Hand), len(player.DropZone)
+
+	if err := party.Ride(player, fromHand); err != nil {
+		t.Fatal(err)
+	}
+
+	if player.Vanguard.TopCard != fromRideDeck || len(player.RideDeck) != 0 {
+		t.Error("the ride deck unit is not the vanguard")
+	}
+	if len(player.Hand) != hand-1 || len(player.DropZone) != drop+1 {
+		t.Errorf("hand %d, drop %d; want %d, %d after the discard", len(player.Hand), len(player.DropZone), hand-1, drop+1)
+	}
+}
+
+func TestRideReplacementCancels(t *testing.T) {
+	party, _ := emptyBoard(t)
+	player := &party.Players[0]
+	vanguard := player.Vanguard.TopCard
+	party.AddReplacement(Replacement{
+		Action:   ActionRide,
+		Duration: UntilEndOfTurn,
+		Replace:  func(party *Party, action *PendingAction) { action.Cancelled = true },
+	})
+
+	if err := party.Ride(player, player.Hand[3]); err != nil {
+		t.Fatal(err)
+	}
+	if player.Vanguard.TopCard != vanguard {
+		t.Error("ride happened")
+	}
+}
+
+func TestPhaseReplacement(t *testing.T) {
+	party, _ := emptyBoard(t)
+	player := &party.Players[0]
+	party.AddReplacement(Replacement{
+		Action:   ActionPhase,
+		Duration: UntilEndOfTurn,
+		Once:     true,
+		Applies:  func(party *Party, action *PendingAction) bool { return action.Phase == PhaseDraw },
+		Replace:  func(party *Party, action *PendingAction) { action.Cancelled = true },
+	})
+	hand := len(player.Hand)
+
+	party.DrawPhase(player)
+	if len(player.Hand) != hand {
+		t.Errorf("drew %d card(s) in a replaced draw phase", len(player.Hand)-hand)
+	}
+	party.DrawPhase(player)
+	if len(player.Hand) != hand+1 {
+		t.Errorf("drew %d card(s) once the replacement was used, want 1", len(player.Hand)-hand)
+	}
+}
+
+// Replacements apply by priority, highest first, then in the order they were added,
+// each one seeing the action as changed by the previous ones.
+func TestReplacementOrder(t *testing.T) {
+	party, _ := emptyBoard(t)
+	player := &party.Players[0]
+	applied := []string{}
+	add := func(name string, priority int, replace func(action *PendingAction)) {
+		party.AddReplacement(Replacement{
+			Action:   ActionDraw,
+			Duration: UntilEndOfTurn,
+			Priority: priority,
+			Applies:  func(party *Party, action *PendingAction) bool { return action.Count < 4 },
+			Replace: func(party *Party, action *PendingAction) {
+				applied = append(applied, name)
+				replace(action)
+			},
+		})
+	}
+	add("double", 0, func(action *PendingAction) { action.Count *= 2 })
+	add("first plus one", 1, func(action *PendingAction) { action.Count++ })
+	add("second plus one", 1, func(action *PendingAction) { action.Count++ })
+	// No longer applies once the count reached 6
+	add("last", -1, func(action *PendingAction) { action.Count = 0 })
+	hand := len(player.Hand)
+
+	party.Draw(player, 1)
+
+	if want := []string{"first plus one", "second plus one", "double"}; !slices.Equal(applied, want) {
+		t.Errorf("applied %v, want %v", applied, want)
+	}
+	if len(player.Hand) != hand+6 {
+		t.Errorf("drew %d card(s), want 6", len(player.Hand)-hand)
+	}
+}
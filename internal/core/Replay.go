@@ -0,0 +1,276 @@
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Decision is an answer given by a player, with the hash of the state it was given in.
+type Decision struct {
+	Player int        `json:"player"`
+	Kind   ChoiceKind `json:"kind"`
+	Answer []int      `json:"answer"`
+	Hash   string     `json:"hash"`
+}
+
+// GameRecord is everything needed to play a game again: the seed, the decks in their
+// starting order and every decision in the order it was taken.
+type GameRecord struct {
+	Seed      string         `json:"seed"`
+	Rules     Rules          `json:"rules"`
+	Decks     []DeckListJSON `json:"decks"`
+	Decisions []Decision     `json:"decisions"`
+	Winner    int            `json:"winner"`
+	FinalHash string         `json:"final_hash"`
+}
+
+// ReplayError tells at which decision a replay left the recorded game.
+type ReplayError struct {
+	Step    int
+	Message string
+}
+
+func (e ReplayError) Error() string {
+	return "replay desync at decision " + strconv.Itoa(e.Step) + ": " + e.Message
+}
+
+// Record returns the record of the game played so far.
+func (party *Party) Record() GameRecord {
+	return GameRecord{
+		Seed:      party.seed,
+		Rules:     party.Rules,
+		Decks:     append([]DeckListJSON{}, party.decklists...),
+		Decisions: append([]Decision{}, party.Decisions...),
+		Winner:    party.Winner,
+		FinalHash: party.StateHash(),
+	}
+}
+
+// recordDecision keeps the answer of a player. While replaying, it is checked against the
+// recorded decision and the game stops at the first difference.
+func (party *Party) recordDecision(player int, kind ChoiceKind, answer []int) {
+	decision := Decision{Player: player, Kind: kind, Answer: append([]int{}, answer...), Hash: party.StateHash()}
+	step := len(party.Decisions)
+	party.Decisions = append(party.Decisions, decision)
+
+	replay := party.replay
+	if replay == nil || replay.err != nil {
+		return
+	}
+	if step >= len(replay.record.Decisions) {
+		// The record stops here, e.g. a game saved before its end
+		party.Decisions = party.Decisions[:step]
+		replay.ended = true
+		party.Over = true
+		return
+	}
+	expected := replay.record.Decisions[step]
+	switch {
+	case expected.Player != decision.Player || expected.Kind != decision.Kind:
+		replay.fail(party, step, fmt.Sprintf("expected a %s decision of player %d, got a %s decision of player %d", expected.Kind, expected.Player, decision.Kind, decision.Player))
+	case expected.Hash != decision.Hash:
+		replay.fail(party, step, "state hash "+decision.Hash+" differs from recorded "+expected.Hash)
+	case !equalAnswers(expected.Answer, decision.Answer):
+		replay.fail(party, step, fmt.Sprintf("answer %v differs from recorded %v", decision.Answer, expected.Answer))
+	default:
+		if replay.onStep != nil {
+			replay.onStep(step, party)
+		}
+	}
+}
+
+func equalAnswers(a []int, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// replayer feeds the recorded decisions back to a party.
+type replayer struct {
+	record GameRecord
+	onStep func(step int, party *Party)
+	err    error
+	// ended is set when the game goes on after the last recorded decision
+	ended bool
+}
+
+func (replay *replayer) fail(party *Party, step int, message string) {
+	replay.err = ReplayError{Step: step, Message: message}
+	// Ends the game at the next rule check
+	party.Over = true
+}
+
+// answer returns the recorded answer of the decision at step.
+func (replay *replayer) answer(step int) []int {
+	if replay.err != nil || step < 0 || step >= len(replay.record.Decisions) {
+		return nil
+	}
+	return replay.record.Decisions[step].Answer
+}
+
+// Replay plays a recorded game again from its seed, decks and decisions. Each decision is
+// checked against the state hash recorded with it, onStep (when not nil) is called after each
+// matching decision so the game can be followed step by step.
+func Replay(record GameRecord, onStep func(step int, party *Party)) (*Party, error) {
+	decks := make([]*Deck, len(record.Decks))
+	for i, list := range record.Decks {
+		deck, err := DeckFromJSON(list, DeckParseOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("deck %d: %w", i, err)
+		}
+		decks[i] = deck
+	}
+
+	party := InitParty(decks)
+	party.Rules = record.Rules
+	replay := &replayer{record: record, onStep: onStep}
+	party.replay = replay
+	InitGame(party, record.Seed)
+
+	party.DecideTurnOrder(func(int, int) {}, func(winnerIndex int) string {
+		if answer := replay.answer(len(party.Decisions)); len(answer) == 1 && answer[0] == 1 {
+			return "second"
+		}
+		return "first"
+	})
+
+	// Mulligans are asked in parallel and recorded afterwards in player order
+	first := len(party.Decisions)
+	party.PerformMulligan(func(playerIndex int, hand []*Card) []int {
+		return replay.answer(first + playerIndex)
+	})
+
+	party.Decide = func(playerIndex int, choice Choice) []int {
+		return replay.answer(len(party.Decisions))
+	}
+	party.Run()
+
+	if replay.err != nil {
+		return party, replay.err
+	}
+	if replay.ended {
+		return party, nil
+	}
+	if len(party.Decisions) != len(record.Decisions) {
+		return party, ReplayError{Step: len(party.Decisions), Message: "game ended before the last recorded decision"}
+	}
+	if party.Winner != record.Winner {
+		return party, ReplayError{Step: len(party.Decisions), Message: fmt.Sprintf("winner %d differs from recorded %d", party.Winner, record.Winner)}
+	}
+	if hash := party.StateHash(); hash != record.FinalHash {
+		return party, ReplayError{Step: len(party.Decisions), Message: "final state " + hash + " differs from recorded " + record.FinalHash}
+	}
+	return party, nil
+}
+
+// LoadGameRecord reads a record saved with SaveGameRecord.
+func LoadGameRecord(path string) (GameRecord, error) {
+	var record GameRecord
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return record, err
+	}
+	if err := json.Unmarshal(data, &record); err != nil {
+		return record, errors.New(path + ": " + err.Error())
+	}
+	return record, nil
+}
+
+// SaveGameRecord writes the record as JSON.
+func SaveGameRecord(path string, record GameRecord) error {
+	data, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// StateHash summarizes the game state: turn, phase, every zone and circle in order with the
+// state of their cards, energy and active modifiers. Cards are identified by card number,
+// their IDs change from one game to the other.
+func (party *Party) StateHash() string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "turn %d %s over %t %d\n", party.Turn, party.CurrentPhase, party.Over, party.Winner)
+
+	names := map[string]string{}
+	writeCards := func(label string, cards []*Card) {
+		builder.WriteString(label + ":")
+		for _, card := range cards {
+			builder.WriteString(" " + cardState(card))
+		}
+		builder.WriteString("\n")
+	}
+	for i := range party.Players {
+		player := &party.Players[i]
+		fmt.Fprintf(&builder, "player %d energy %d\n", i, player.Energy)
+		for _, zone := range cardZones {
+			cards := *player.zone(zone)
+			writeCards(string(zone), cards)
+			for _, card := range cards {
+				names[card.ID] = card.CardNumberFull
+			}
+		}
+		for _, id := range AllCircles {
+			card := player.Circle(id).TopCard
+			if card == nil {
+				builder.WriteString(string(id) + ": -\n")
+				continue
+			}
+			writeCards(string(id), []*Card{card})
+			names[card.ID] = card.CardNumberFull
+		}
+	}
+	for _, modifier := range party.Modifiers {
+		fmt.Fprintf(&builder, "modifier %s %s %d %s\n", names[modifier.CardID], modifier.Stat, modifier.Amount, modifier.Duration)
+	}
+	fmt.Fprintf(&builder, "replacements %d\n", len(party.Replacements))
+
+	sum := sha256.Sum256([]byte(builder.String()))
+	return hex.EncodeToString(sum[:8])
+}
+
+func cardState(card *Card) string {
+	state := card.CardNumberFull
+	if card.Rested {
+		state += "/R"
+	}
+	if card.Locked {
+		state += "/L"
+	}
+	if card.FaceDown {
+		state += "/D"
+	}
+	return state
+}
+
+// orderedDeckList lists the deck in its exact order, grouping only consecutive copies,
+// so the deck rebuilt from it is shuffled the same way.
+func orderedDeckList(deck *Deck) DeckListJSON {
+	runs := func(cards []*Card) []DeckListJSONCard {
+		list := []DeckListJSONCard{}
+		for _, card := range cards {
+			if card == nil {
+				continue
+			}
+			if last := len(list) - 1; last >= 0 && list[last].CardNumber == card.CardNumberFull {
+				list[last].Count++
+				continue
+			}
+			list = append(list, DeckListJSONCard{Count: 1, CardNumber: card.CardNumberFull, Name: card.Name})
+		}
+		return list
+	}
+	return DeckListJSON{Ride: runs(deck.RideDeck), Main: runs(deck.MainDeck), G: runs(deck.GDeck)}
+}
@@ -0,0 +1,131 @@
+package core
+
+import (
+	"errors"
+	"math/rand"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func replayTestDeck() DeckListJSON {
+	return DeckListJSON{
+		Ride: []DeckListJSONCard{
+			{Count: 1, CardNumber: "TEST/000"},
+			{Count: 1, CardNumber: "TEST/001"},
+			{Count: 1, CardNumber: "TEST/002"},
+			{Count: 1, CardNumber: "TEST/003"},
+		},
+		Main: []DeckListJSONCard{
+			{Count: 15, CardNumber: "TEST/001"},
+			{Count: 11, CardNumber: "TEST/002"},
+			{Count: 8, CardNumber: "TEST/003"},
+			{Count: 8, CardNumber: "TEST/T01"},
+			{Count: 4, CardNumber: "TEST/T02"},
+			{Count: 4, CardNumber: "TEST/T03"},
+		},
+	}
+}
+
+// playRecordedGame plays a whole game from the seed, both players answering every
+// decision at random from their own seeded source.
+func playRecordedGame(t *testing.T, seed string) GameRecord {
+	t.Helper()
+	decks := []*Deck{}
+	for range 2 {
+		deck, err := DeckFromJSON(replayTestDeck(), DeckParseOptions{})
+		if err != nil {
+			t.Fatalf("deck: %v", err)
+		}
+		decks = append(decks, deck)
+	}
+
+	party := InitParty(decks)
+	party.Rules = DefaultRules()
+	InitGame(party, seed)
+
+	answers := rand.New(rand.NewSource(42))
+	party.DecideTurnOrder(func(int, int) {}, func(int) string {
+		return []string{"first", "second"}[answers.Intn(2)]
+	})
+	party.PerformMulligan(func(playerIndex int, hand []*Card) []int {
+		return []int{playerIndex}
+	})
+	party.Decide = func(playerIndex int, choice Choice) []int {
+		count := choice.Min + answers.Intn(choice.Max-choice.Min+1)
+		return answers.Perm(len(choice.Options))[:count]
+	}
+	party.Run()
+
+	if !party.Over {
+		t.Fatal("game did not end")
+	}
+	return party.Record()
+}
+
+func TestReplay(t *testing.T) {
+	SetCardDatabase(testCardDatabase())
+	t.Cleanup(func() { SetCardDatabase(nil) })
+
+	played := playRecordedGame(t, "20240601")
+	if played.Winner < 0 {
+		t.Fatalf("game ended without a winner")
+	}
+	if len(played.Decisions) < 10 {
+		t.Fatalf("only %d decisions recorded", len(played.Decisions))
+	}
+
+	// The record goes through its JSON form, as saved after a game
+	path := filepath.Join(t.TempDir(), "game.json")
+	if err := SaveGameRecord(path, played); err != nil {
+		t.Fatal(err)
+	}
+	record, err := LoadGameRecord(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.Rules != played.Rules {
+		t.Fatalf("rules %+v loaded as %+v", played.Rules, record.Rules)
+	}
+
+	t.Run("clean", func(t *testing.T) {
+		steps := 0
+		party, err := Replay(record, func(step int, party *Party) { steps++ })
+		if err != nil {
+			t.Fatal(err)
+		}
+		if steps != len(record.Decisions) {
+			t.Errorf("%d steps followed, want %d", steps, len(record.Decisions))
+		}
+		if party.Winner != record.Winner || party.StateHash() != record.FinalHash {
+			t.Errorf("replay ended with winner %d, state %s; recorded %d, %s", party.Winner, party.StateHash(), record.Winner, record.FinalHash)
+		}
+	})
+
+	t.Run("altered answer", func(t *testing.T) {
+		altered := record
+		altered.Decisions = append([]Decision{}, record.Decisions...)
+		// A guard called in the second half of the game is not called anymore: the answer is
+		// still valid, the state hash of the next decision tells the game went another way
+		step := -1
+		for i, decision := range altered.Decisions {
+			if i > len(altered.Decisions)/2 && decision.Kind == ChoiceGuard && len(decision.Answer) > 0 {
+				step = i
+				break
+			}
+		}
+		if step < 0 {
+			t.Fatal("no decision to alter")
+		}
+		altered.Decisions[step].Answer = []int{}
+
+		_, err := Replay(altered, nil)
+		var replayErr ReplayError
+		if !errors.As(err, &replayErr) {
+			t.Fatalf("got %v, want a ReplayError", err)
+		}
+		if replayErr.Step <= step || !strings.Contains(replayErr.Message, "state hash") {
+			t.Errorf("decision %d altered, got %v, want a state hash desync after it", step, replayErr)
+		}
+	})
+}
@@ -0,0 +1,78 @@
+package core
+
+import "fmt"
+
+// MaxResolutionSteps caps the abilities resolved in a row, abilities triggering each other
+// forever are stopped there.
+const MaxResolutionSteps = 500
+
+// resolveEvents resolves the waiting abilities: the turn player resolves theirs first, choosing
+// the order when several wait, then the other player. Abilities triggered meanwhile join the queue
+// and are resolved in the same way. Rules are checked between resolutions, and each resolved
+// event is logged.
+func (party *Party) resolveEvents() {
+	if party.resolving {
+		return
+	}
+	party.resolving = true
+	defer func() { party.resolving = false }()
+
+	for steps := 0; len(party.EventQueue) > 0; steps++ {
+		if party.ruleCheck() {
+			party.EventQueue = []Event{}
+			return
+		}
+		if steps >= MaxResolutionSteps {
+			fmt.Printf("Resolution: stopped after %d steps, %d event(s) dropped\n", steps, len(party.EventQueue))
+			party.EventQueue = []Event{}
+			// The game cannot go on, it ends in a draw
+			party.Over = true
+			party.Winner = -1
+			party.EndReason = fmt.Sprintf("draw: abilities still triggering after %d resolutions", steps)
+			party.log(LogEntry{Action: LogGameOver, Player: -1, Detail: party.EndReason})
+			return
+		}
+
+		event := party.nextEvent()
+		if event.FuncCall != nil {
+			event.FuncCall()
+		}
+	}
+	party.ruleCheck()
+}
+
+// nextEvent removes and returns the event to resolve next. It belongs to the first player in
+// turn order with a waiting event, who picks one when they have several.
+func (party *Party) nextEvent() Event {
+	for _, playerIndex := range party.activePlayerOrder() {
+		waiting := []int{}
+		for i, event := range party.EventQueue {
+			if event.PlayerIndex == playerIndex {
+				waiting = append(waiting, i)
+			}
+		}
+		if len(waiting) == 0 {
+			continue
+		}
+
+		index := waiting[0]
+		if len(waiting) > 1 {
+			options := make([]string, len(waiting))
+			for i, queueIndex := range waiting {
+				options[i] = party.EventQueue[queueIndex].Description
+			}
+			chosen := party.choose(&party.Players[playerIndex], Choice{Kind: ChoiceOrder, Prompt: "Choose the ability to resolve first", Options: options, Min: 1, Max: 1})
+			index = waiting[chosen[0]]
+		}
+		return party.takeEvent(index)
+	}
+
+	// Events of no player resolve in the order they were queued
+	return party.takeEvent(0)
+}
+
+func (party *Party) takeEvent(index int) Event {
+	event := party.EventQueue[index]
+	party.EventQueue = append(party.EventQueue[:index:index], party.EventQueue[index+1:]...)
+	return event
+}
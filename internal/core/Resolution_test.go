@@ -0,0 +1,33 @@
+package core
+
+import "testing"
+
+// An ability triggering itself forever stops at MaxResolutionSteps and the game ends in a draw.
+func TestEndlessTriggersEndInDraw(t *testing.T) {
+	const timing = "TEST_LOOP"
+	script := CardScript{Key: "Looping Card", Abilities: func() []Ability {
+		return []Ability{{
+			Kind:        AbilityAUTO,
+			Description: "Triggers itself",
+			Timing:      timing,
+			Effect: func(party *Party, player *Player, source *Card) bool {
+				party.emit(timing, source)
+				return true
+			},
+		}}
+	}}
+	party, card := sampleBoard(script.Key, script)
+
+	party.emit(timing, card)
+
+	if !party.Over || party.Winner != -1 {
+		t.Fatalf("over %t, winner %d, want a draw", party.Over, party.Winner)
+	}
+	if len(party.EventQueue) != 0 {
+		t.Errorf("%d event(s) left in queue", len(party.EventQueue))
+	}
+	last := party.History[len(party.History)-1]
+	if last.Action != LogGameOver || last.Player != -1 || last.Detail != party.EndReason {
+		t.Errorf("last log entry is %+v, want the draw", last)
+	}
+}
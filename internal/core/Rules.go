@@ -0,0 +1,57 @@
+package core
+
+import "strings"
+
+// Rules holds the format dependent parts of the game flow, set per party.
+type Rules struct {
+	Format string `json:"format"`
+	// SkipFirstDraw: the player going first does not draw on turn 1
+	SkipFirstDraw bool `json:"skip_first_draw"`
+	// NoFirstTurnBattle: the player going first has no battle phase on turn 1
+	NoFirstTurnBattle bool `json:"no_first_turn_battle"`
+	// SecondPlayerEnergy is charged by the player going second at the start of the game
+	SecondPlayerEnergy int `json:"second_player_energy"`
+	// HandLimit is the hand size the turn player discards down to in the End Phase, 0 for none
+	HandLimit int `json:"hand_limit"`
+}
+
+// FormatRules are the presets selectable by name.
+var FormatRules = map[string]Rules{
+	"d": {
+		Format:             "D",
+		NoFirstTurnBattle:  true,
+		SecondPlayerEnergy: 1,
+	},
+	"premium": {
+		Format:            "Premium",
+		NoFirstTurnBattle: true,
+	},
+	"casual": {
+		Format: "Casual",
+	},
+}
+
+// DefaultRules are the D Format rules.
+func DefaultRules() Rules {
+	return FormatRules["d"]
+}
+
+// RulesFor returns the preset for a format name, case insensitive.
+func RulesFor(format string) (Rules, bool) {
+	rules, ok := FormatRules[strings.ToLower(strings.TrimSpace(format))]
+	return rules, ok
+}
+
+// isFirstTurn reports whether this is the first turn of the player going first.
+func (party *Party) isFirstTurn() bool {
+	return party.Turn == 1
+}
+
+// startGame applies the start of game rules once the turn order is known.
+func (party *Party) startGame() {
+	if len(party.Players) > 1 && party.Rules.SecondPlayerEnergy > 0 {
+		party.Players[1].ChargeEnergy(party.Rules.SecondPlayerEnergy)
+		party.log(LogEntry{Action: LogEnergy, Player: 1, Amount: party.Rules.SecondPlayerEnergy})
+		println("Player 1 goes second: Energy Charge", party.Rules.SecondPlayerEnergy)
+	}
+}
@@ -0,0 +1,374 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CardScriptsEnv lists card script files or directories to load, separated by the OS path list separator.
+const CardScriptsEnv = "VG_CARD_SCRIPTS"
+
+// Card script files are JSON arrays of scripts:
+//
+//	[
+//	  {
+//	    "card": "DZ-TD04/001EN",
+//	    "abilities": [
+//	      {
+//	        "kind": "AUTO",
+//	        "description": "When this unit attacks, COST [Counter Blast 1], draw a card",
+//	        "timing": "WHEN_ATTACKS",
+//	        "on_self": true,
+//	        "limit": "1/Turn",
+//	        "condition": ["VanguardGradeAtLeast", 3],
+//	        "cost": {"counter_blast": 1},
+//	        "effect": ["Then", ["Draw", 1], ["PowerUp", 5000]]
+//	      }
+//	    ]
+//	  }
+//	]
+//
+// Conditions, effects, targets, filters and counters are written ["Name", arguments...],
+// or just "Name" without arguments, using the names of ConditionLib, EffectLib and Target.
+
+// ScriptError points at the script and line a problem comes from.
+type ScriptError struct {
+	File    string
+	Line    int
+	Card    string
+	Message string
+}
+
+func (e ScriptError) Error() string {
+	location := e.File
+	if e.Line > 0 {
+		location += ":" + strconv.Itoa(e.Line)
+	}
+	if e.Card != "" {
+		location += ": " + e.Card
+	}
+	return location + ": " + e.Message
+}
+
+// ScriptErrors holds every problem found while loading scripts.
+type ScriptErrors []ScriptError
+
+func (errs ScriptErrors) Error() string {
+	lines := make([]string, len(errs))
+	for i, err := range errs {
+		lines[i] = err.Error()
+	}
+	return strings.Join(lines, "\n")
+}
+
+type scriptFileEntry struct {
+	Card      string            `json:"card"`
+	Zone      Zone              `json:"zone"`
+	Abilities []json.RawMessage `json:"abilities"`
+}
+
+type scriptAbility struct {
+	Kind        AbilityKind     `json:"kind"`
+	Description string          `json:"description"`
+	Timing      string          `json:"timing"`
+	OnSelf      bool            `json:"on_self"`
+	Limit       string          `json:"limit"`
+	Condition   json.RawMessage `json:"condition"`
+	Cost        json.RawMessage `json:"cost"`
+	Effect      json.RawMessage `json:"effect"`
+}
+
+type scriptCost struct {
+	CounterBlast int `json:"counter_blast"`
+	SoulBlast    int `json:"soul_blast"`
+	Discard      int `json:"discard"`
+	EnergyBlast  int `json:"energy_blast"`
+}
+
+// CardScriptPaths returns the script files or directories configured in VG_CARD_SCRIPTS.
+func CardScriptPaths() []string {
+	paths := []string{}
+	for _, path := range filepath.SplitList(os.Getenv(CardScriptsEnv)) {
+		if path = strings.TrimSpace(path); path != "" {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// LoadCardScripts loads script files, directories loading every .json file they contain.
+// Valid scripts are registered even when others fail, all problems are returned as ScriptErrors.
+func LoadCardScripts(paths ...string) error {
+	var errs ScriptErrors
+	for _, path := range paths {
+		files := []string{path}
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			files, _ = filepath.Glob(filepath.Join(path, "*.json"))
+			sort.Strings(files)
+		}
+		for _, file := range files {
+			if err := LoadCardScriptFile(file); err != nil {
+				var fileErrs ScriptErrors
+				if errors.As(err, &fileErrs) {
+					errs = append(errs, fileErrs...)
+				} else {
+					errs = append(errs, ScriptError{File: file, Message: err.Error()})
+				}
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// LoadCardScriptFile parses one script file and registers its valid scripts.
+func LoadCardScriptFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var errs ScriptErrors
+	lineOf := func(offset int64) int {
+		return 1 + bytes.Count(data[:offset], []byte("\n"))
+	}
+
+	entries, err := jsonArrayElements(data, 0)
+	if err != nil {
+		return ScriptErrors{{File: path, Line: lineOf(syntaxOffset(err)), Message: err.Error()}}
+	}
+
+	for _, element := range entries {
+		var entry scriptFileEntry
+		if err := strictUnmarshal(element.raw, &entry); err != nil {
+			errs = append(errs, ScriptError{File: path, Line: lineOf(element.offset), Message: err.Error()})
+			continue
+		}
+		if strings.TrimSpace(entry.Card) == "" {
+			errs = append(errs, ScriptError{File: path, Line: lineOf(element.offset), Message: "missing \"card\""})
+			continue
+		}
+		if entry.Zone != "" && entry.Zone != ZoneField && (&Player{}).zone(entry.Zone) == nil {
+			errs = append(errs, ScriptError{File: path, Line: lineOf(element.offset), Card: entry.Card, Message: "unknown zone " + strconv.Quote(string(entry.Zone))})
+			continue
+		}
+
+		abilityOffsets := map[int]int64{}
+		if field, ok := jsonObjectField(element.raw, "abilities"); ok {
+			if elements, err := jsonArrayElements(field.raw, 0); err == nil {
+				for i, ability := range elements {
+					abilityOffsets[i] = element.offset + field.offset + ability.offset
+				}
+			}
+		}
+
+		valid := true
+		for i, raw := range entry.Abilities {
+			if _, err := buildScriptAbility(raw); err != nil {
+				offset, ok := abilityOffsets[i]
+				if !ok {
+					offset = element.offset
+				}
+				errs = append(errs, ScriptError{File: path, Line: lineOf(offset), Card: entry.Card, Message: "ability #" + strconv.Itoa(i+1) + ": " + err.Error()})
+				valid = false
+			}
+		}
+		if !valid {
+			continue
+		}
+
+		abilities := entry.Abilities
+		RegisterCardScript(CardScript{Key: entry.Card, Zone: entry.Zone, Abilities: func() []Ability {
+			result := make([]Ability, 0, len(abilities))
+			for _, raw := range abilities {
+				// Already validated when the file was loaded
+				ability, _ := buildScriptAbility(raw)
+				result = append(result, ability)
+			}
+			return result
+		}})
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func buildScriptAbility(raw json.RawMessage) (Ability, error) {
+	var spec scriptAbility
+	if err := strictUnmarshal(raw, &spec); err != nil {
+		return Ability{}, err
+	}
+
+	ability := Ability{Kind: spec.Kind, Description: spec.Description, Timing: spec.Timing, OnSelf: spec.OnSelf}
+	switch spec.Kind {
+	case AbilityACT, AbilityCONT:
+	case AbilityAUTO:
+		if spec.Timing == "" {
+			return ability, errors.New("AUTO ability needs a \"timing\"")
+		}
+		if !knownTiming(spec.Timing) {
+			return ability, errors.New("unknown timing " + strconv.Quote(spec.Timing))
+		}
+	default:
+		return ability, errors.New("unknown kind " + strconv.Quote(string(spec.Kind)) + ", expected ACT, AUTO or CONT")
+	}
+
+	if spec.Limit != "" {
+		limit, err := parseUsageLimit(spec.Limit)
+		if err != nil {
+			return ability, err
+		}
+		ability.Limit = limit
+	}
+
+	if len(spec.Condition) > 0 {
+		condition, err := decodeScriptValue(spec.Condition, kindCondition)
+		if err != nil {
+			return ability, fmt.Errorf("condition: %w", err)
+		}
+		ability.Condition = condition.(Condition)
+	}
+
+	if len(spec.Cost) > 0 {
+		cost, err := decodeScriptValue(spec.Cost, kindCost)
+		if err != nil {
+			return ability, fmt.Errorf("cost: %w", err)
+		}
+		ability.Cost = cost.(Cost)
+	}
+
+	if len(spec.Effect) == 0 {
+		if spec.Kind != AbilityCONT {
+			return ability, errors.New("missing \"effect\"")
+		}
+		return ability, nil
+	}
+	effect, err := decodeScriptValue(spec.Effect, kindEffect)
+	if err != nil {
+		return ability, fmt.Errorf("effect: %w", err)
+	}
+	ability.Effect = effect.(EffectAction)
+	return ability, nil
+}
+
+// parseUsageLimit reads "1/Turn" or "1/Fight".
+func parseUsageLimit(text string) (UsageLimit, error) {
+	count, scope, found := strings.Cut(text, "/")
+	n, err := strconv.Atoi(strings.TrimSpace(count))
+	if !found || err != nil || n <= 0 {
+		return UsageLimit{}, errors.New("invalid limit " + strconv.Quote(text) + ", expected like \"1/Turn\"")
+	}
+	switch {
+	case strings.EqualFold(strings.TrimSpace(scope), string(LimitTurn)):
+		return UsageLimit{Scope: LimitTurn, Count: n}, nil
+	case strings.EqualFold(strings.TrimSpace(scope), string(LimitFight)):
+		return UsageLimit{Scope: LimitFight, Count: n}, nil
+	}
+	return UsageLimit{}, errors.New("invalid limit " + strconv.Quote(text) + ", expected Turn or Fight")
+}
+
+// knownTiming accepts the timings emitted by the engine.
+func knownTiming(timing string) bool {
+	timings := []string{
+		TimingBoosted, TimingBoosting, TimingAttack, TimingAttacked, TimingDriveCheck, TimingDamageCheck, TimingHit, TimingEndOfBattle,
+		TimingPlaced, TimingRetired, TimingBound, TimingLocked, TimingUnlocked, TimingReturnedToHand, TimingPutIntoDeck,
+		TimingSoulCharged, TimingCounterCharged, TimingPutIntoDropZone, TimingPutIntoHand, TimingPutIntoSoul, TimingRide,
+		TimingEndOfTurn,
+	}
+	for _, phase := range []string{PhaseStand, PhaseDraw, PhaseRide, PhaseMain, PhaseBattle, PhaseEnd} {
+		timings = append(timings, PhaseTiming("START_", phase), PhaseTiming("END_", phase))
+	}
+	for _, known := range timings {
+		if timing == known {
+			return true
+		}
+	}
+	return false
+}
+
+func strictUnmarshal(raw json.RawMessage, value interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(value)
+}
+
+// jsonElement is a JSON value and its byte offset in the enclosing document.
+type jsonElement struct {
+	raw    json.RawMessage
+	offset int64
+}
+
+// jsonArrayElements splits a JSON array, keeping the offset of each element for error lines.
+func jsonArrayElements(data []byte, base int64) ([]jsonElement, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return nil, errors.New("expected a JSON array")
+	}
+
+	elements := []jsonElement{}
+	for decoder.More() {
+		start := skipJSONSeparators(data, decoder.InputOffset())
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			return nil, err
+		}
+		elements = append(elements, jsonElement{raw: raw, offset: base + start})
+	}
+	if _, err := decoder.Token(); err != nil {
+		return nil, err
+	}
+	return elements, nil
+}
+
+// jsonObjectField returns the value of a key of a JSON object, with its offset in the object.
+func jsonObjectField(data []byte, key string) (jsonElement, bool) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return jsonElement{}, false
+	}
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return jsonElement{}, false
+		}
+		start := skipJSONSeparators(data, decoder.InputOffset())
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			return jsonElement{}, false
+		}
+		if name, ok := token.(string); ok && name == key {
+			return jsonElement{raw: raw, offset: start}, true
+		}
+	}
+	return jsonElement{}, false
+}
+
+func skipJSONSeparators(data []byte, offset int64) int64 {
+	for offset < int64(len(data)) && strings.ContainsRune(" \t\r\n,:", rune(data[offset])) {
+		offset++
+	}
+	return offset
+}
+
+func syntaxOffset(err error) int64 {
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return syntaxErr.Offset
+	}
+	return 0
+}
@@ -0,0 +1,294 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// scriptKind is the type of a value in a card script.
+type scriptKind string
+
+const (
+	kindInt       scriptKind = "number"
+	kindString    scriptKind = "string"
+	kindBool      scriptKind = "boolean"
+	kindCondition scriptKind = "condition"
+	kindEffect    scriptKind = "effect"
+	kindSelector  scriptKind = "selector"
+	kindTarget    scriptKind = "target"
+	kindFilter    scriptKind = "filter"
+	kindCounter   scriptKind = "counter"
+	kindCost      scriptKind = "cost"
+	kindZone      scriptKind = "zone"
+	kindStat      scriptKind = "stat"
+	kindRest      scriptKind = "rest placement"
+	kindTrigger   scriptKind = "trigger type"
+	kindCardType  scriptKind = "card type"
+)
+
+// scriptFunc maps a script name to its builder. With variadic set, the last parameter repeats.
+type scriptFunc struct {
+	result   scriptKind
+	params   []scriptKind
+	variadic bool
+	build    func(args []interface{}) interface{}
+}
+
+func params(kinds ...scriptKind) []scriptKind {
+	return kinds
+}
+
+var scriptFuncs = map[string]scriptFunc{
+	// ConditionLib
+	"IsPhase":                {kindCondition, params(kindString), false, func(a []interface{}) interface{} { return IsPhase(a[0].(string)) }},
+	"IsTurnPlayer":           {kindCondition, nil, false, func(a []interface{}) interface{} { return IsTurnPlayer() }},
+	"HasGradeGreaterOrEqual": {kindCondition, params(kindInt), false, func(a []interface{}) interface{} { return HasGradeGreaterOrEqual(a[0].(int)) }},
+	"HasUnitInVanguard":      {kindCondition, params(kindString), false, func(a []interface{}) interface{} { return HasUnitInVanguard(a[0].(string)) }},
+	"And":                    {kindCondition, params(kindCondition), true, func(a []interface{}) interface{} { return And(conditions(a)...) }},
+	"Or":                     {kindCondition, params(kindCondition), true, func(a []interface{}) interface{} { return Or(conditions(a)...) }},
+	"Not":                    {kindCondition, params(kindCondition), false, func(a []interface{}) interface{} { return Not(a[0].(Condition)) }},
+	"Opponent":               {kindCondition, params(kindCondition), false, func(a []interface{}) interface{} { return Opponent(a[0].(Condition)) }},
+	"ZoneCountAtLeast":       {kindCondition, params(kindZone, kindInt), false, func(a []interface{}) interface{} { return ZoneCountAtLeast(a[0].(Zone), a[1].(int)) }},
+	"DamageAtLeast":          {kindCondition, params(kindInt), false, func(a []interface{}) interface{} { return DamageAtLeast(a[0].(int)) }},
+	"SoulAtLeast":            {kindCondition, params(kindInt), false, func(a []interface{}) interface{} { return SoulAtLeast(a[0].(int)) }},
+	"HandAtLeast":            {kindCondition, params(kindInt), false, func(a []interface{}) interface{} { return HandAtLeast(a[0].(int)) }},
+	"DropAtLeast":            {kindCondition, params(kindInt), false, func(a []interface{}) interface{} { return DropAtLeast(a[0].(int)) }},
+	"FaceUpDamageAtLeast":    {kindCondition, params(kindInt), false, func(a []interface{}) interface{} { return FaceUpDamageAtLeast(a[0].(int)) }},
+	"VanguardMatches":        {kindCondition, params(kindFilter), false, func(a []interface{}) interface{} { return VanguardMatches(a[0].(CardFilter)) }},
+	"VanguardGradeAtLeast":   {kindCondition, params(kindInt), false, func(a []interface{}) interface{} { return VanguardGradeAtLeast(a[0].(int)) }},
+	"VanguardHasClan":        {kindCondition, params(kindString), false, func(a []interface{}) interface{} { return VanguardHasClan(a[0].(string)) }},
+	"RearGuardsAtLeast":      {kindCondition, params(kindFilter, kindInt), false, func(a []interface{}) interface{} { return RearGuardsAtLeast(a[0].(CardFilter), a[1].(int)) }},
+	"HasRearGuardNamed":      {kindCondition, params(kindString), false, func(a []interface{}) interface{} { return HasRearGuardNamed(a[0].(string)) }},
+	"HasRearGuardWithClan":   {kindCondition, params(kindString), false, func(a []interface{}) interface{} { return HasRearGuardWithClan(a[0].(string)) }},
+	"OnVanguardCircle":       {kindCondition, nil, false, func(a []interface{}) interface{} { return OnVanguardCircle() }},
+	"OnRearGuardCircle":      {kindCondition, nil, false, func(a []interface{}) interface{} { return OnRearGuardCircle() }},
+	"SourceMatches":          {kindCondition, params(kindFilter), false, func(a []interface{}) interface{} { return SourceMatches(a[0].(CardFilter)) }},
+	"InBattle":               {kindCondition, nil, false, func(a []interface{}) interface{} { return InBattle() }},
+	"IsAttacking":            {kindCondition, nil, false, func(a []interface{}) interface{} { return IsAttacking() }},
+	"IsAttacked":             {kindCondition, nil, false, func(a []interface{}) interface{} { return IsAttacked() }},
+	"IsBoosted":              {kindCondition, nil, false, func(a []interface{}) interface{} { return IsBoosted() }},
+	"IsBoosting":             {kindCondition, nil, false, func(a []interface{}) interface{} { return IsBoosting() }},
+	"AttackerMatches":        {kindCondition, params(kindFilter), false, func(a []interface{}) interface{} { return AttackerMatches(a[0].(CardFilter)) }},
+	"AttackedByVanguard":     {kindCondition, nil, false, func(a []interface{}) interface{} { return AttackedByVanguard() }},
+	"IsHit":                  {kindCondition, nil, false, func(a []interface{}) interface{} { return IsHit() }},
+
+	// EffectLib, effect names drop the "Effect" suffix
+	"Then":    {kindEffect, params(kindEffect), true, func(a []interface{}) interface{} { return Then(effects(a)...) }},
+	"IfYouDo": {kindEffect, params(kindEffect, kindEffect), false, func(a []interface{}) interface{} { return IfYouDo(a[0].(EffectAction), a[1].(EffectAction)) }},
+	"YouMay":  {kindEffect, params(kindString, kindEffect), false, func(a []interface{}) interface{} { return YouMay(a[0].(string), a[1].(EffectAction)) }},
+	"OnlyIf":  {kindEffect, params(kindCondition, kindEffect), false, func(a []interface{}) interface{} { return OnlyIf(a[0].(Condition), a[1].(EffectAction)) }},
+	"ForEach": {kindEffect, params(kindCounter, kindEffect), false, func(a []interface{}) interface{} { return ForEach(a[0].(Counter), a[1].(EffectAction)) }},
+	"Repeat":  {kindEffect, params(kindInt, kindEffect), false, func(a []interface{}) interface{} { return Repeat(a[0].(int), a[1].(EffectAction)) }},
+	"PayCost": {kindEffect, params(kindCost), false, func(a []interface{}) interface{} { return PayCostEffect(a[0].(Cost)) }},
+	"Draw":    {kindEffect, params(kindInt), false, func(a []interface{}) interface{} { return DrawEffect(a[0].(int)) }},
+	"PowerUp": {kindEffect, params(kindInt), false, func(a []interface{}) interface{} { return PowerUpEffect(a[0].(int)) }},
+	"ModifyStat": {kindEffect, params(kindSelector, kindStat, kindInt), false, func(a []interface{}) interface{} {
+		return ModifyStatEffect(a[0].(CardSelector), a[1].(Stat), a[2].(int))
+	}},
+	"DriveModifier": {kindEffect, params(kindInt), false, func(a []interface{}) interface{} { return DriveModifierEffect(a[0].(int)) }},
+	"CannotBeHit":   {kindEffect, nil, false, func(a []interface{}) interface{} { return CannotBeHitEffect() }},
+	"RetireUnit":    {kindEffect, params(kindSelector), false, func(a []interface{}) interface{} { return RetireUnitEffect(a[0].(CardSelector)) }},
+	"CannotBeRetired": {kindEffect, params(kindSelector), false, func(a []interface{}) interface{} {
+		return CannotBeRetiredEffect(a[0].(CardSelector))
+	}},
+	"PreventDamage": {kindEffect, params(kindInt), false, func(a []interface{}) interface{} { return PreventDamageEffect(a[0].(int)) }},
+	"Bind":          {kindEffect, params(kindSelector, kindBool), false, func(a []interface{}) interface{} { return BindEffect(a[0].(CardSelector), a[1].(bool)) }},
+	"Lock":          {kindEffect, params(kindSelector), false, func(a []interface{}) interface{} { return LockEffect(a[0].(CardSelector)) }},
+	"Unlock":        {kindEffect, params(kindSelector), false, func(a []interface{}) interface{} { return UnlockEffect(a[0].(CardSelector)) }},
+	"ReturnToHand":  {kindEffect, params(kindSelector), false, func(a []interface{}) interface{} { return ReturnToHandEffect(a[0].(CardSelector)) }},
+	"PutIntoDeck":   {kindEffect, params(kindSelector, kindBool), false, func(a []interface{}) interface{} { return PutIntoDeckEffect(a[0].(CardSelector), a[1].(bool)) }},
+	"EnergyCharge":  {kindEffect, params(kindInt), false, func(a []interface{}) interface{} { return EnergyChargeEffect(a[0].(int)) }},
+	"SoulCharge":    {kindEffect, params(kindInt), false, func(a []interface{}) interface{} { return SoulChargeEffect(a[0].(int)) }},
+	"CounterCharge": {kindEffect, params(kindInt), false, func(a []interface{}) interface{} { return CounterChargeEffect(a[0].(int)) }},
+	"SuperiorCall":  {kindEffect, params(kindZone, kindInt), false, func(a []interface{}) interface{} { return SuperiorCallEffect(a[0].(Zone), a[1].(int)) }},
+	"LookAtTop": {kindEffect, params(kindInt, kindFilter, kindInt, kindZone, kindRest), false, func(a []interface{}) interface{} {
+		return LookAtTopEffect(a[0].(int), a[1].(CardFilter), a[2].(int), a[3].(Zone), a[4].(RestPlacement))
+	}},
+	"SearchDeck": {kindEffect, params(kindFilter, kindInt, kindZone), false, func(a []interface{}) interface{} { return SearchDeckEffect(a[0].(CardFilter), a[1].(int), a[2].(Zone)) }},
+	"RevealTop":  {kindEffect, params(kindInt), false, func(a []interface{}) interface{} { return RevealTopEffect(a[0].(int)) }},
+	"Reveal":     {kindEffect, params(kindSelector), false, func(a []interface{}) interface{} { return RevealEffect(a[0].(CardSelector)) }},
+	"Shuffle":    {kindEffect, nil, false, func(a []interface{}) interface{} { return ShuffleEffect() }},
+
+	// Counters
+	"Fixed":        {kindCounter, params(kindInt), false, func(a []interface{}) interface{} { return Fixed(a[0].(int)) }},
+	"CountTargets": {kindCounter, params(kindTarget), false, func(a []interface{}) interface{} { return CountTargets(a[0].(Target)) }},
+	"CountZone":    {kindCounter, params(kindZone, kindFilter), false, func(a []interface{}) interface{} { return CountZone(a[0].(Zone), a[1].(CardFilter)) }},
+
+	// Targets, a target can be given wherever a selector is expected
+	"ThisCard":           {kindSelector, nil, false, func(a []interface{}) interface{} { return ThisCard() }},
+	"YourUnits":          {kindTarget, params(kindInt), false, func(a []interface{}) interface{} { return YourUnits(a[0].(int)) }},
+	"YourRearGuards":     {kindTarget, params(kindInt), false, func(a []interface{}) interface{} { return YourRearGuards(a[0].(int)) }},
+	"YourVanguard":       {kindTarget, nil, false, func(a []interface{}) interface{} { return YourVanguard() }},
+	"OpponentUnits":      {kindTarget, params(kindInt), false, func(a []interface{}) interface{} { return OpponentUnits(a[0].(int)) }},
+	"OpponentRearGuards": {kindTarget, params(kindInt), false, func(a []interface{}) interface{} { return OpponentRearGuards(a[0].(int)) }},
+	"OpponentFrontRow":   {kindTarget, params(kindInt), false, func(a []interface{}) interface{} { return OpponentFrontRow(a[0].(int)) }},
+	"CardsIn":            {kindTarget, params(kindZone, kindInt), false, func(a []interface{}) interface{} { return CardsIn(a[0].(Zone), a[1].(int)) }},
+	"AllOf":              {kindTarget, params(kindTarget), false, func(a []interface{}) interface{} { return a[0].(Target).AllOf() }},
+	"UpTo":               {kindTarget, params(kindTarget), false, func(a []interface{}) interface{} { return a[0].(Target).UpToCount() }},
+	"Where":              {kindTarget, params(kindTarget, kindFilter), false, func(a []interface{}) interface{} { return a[0].(Target).Where(a[1].(CardFilter)) }},
+	"ChosenByOwner":      {kindTarget, params(kindTarget), false, func(a []interface{}) interface{} { return a[0].(Target).ChosenByOwner() }},
+
+	// Card filters
+	"AnyCard":        {kindFilter, nil, false, func(a []interface{}) interface{} { return AnyCard() }},
+	"IsGrade":        {kindFilter, params(kindInt), false, func(a []interface{}) interface{} { return IsGrade(a[0].(int)) }},
+	"GradeOrLess":    {kindFilter, params(kindInt), false, func(a []interface{}) interface{} { return GradeOrLess(a[0].(int)) }},
+	"GradeOrGreater": {kindFilter, params(kindInt), false, func(a []interface{}) interface{} { return GradeOrGreater(a[0].(int)) }},
+	"IsCardType":     {kindFilter, params(kindCardType), false, func(a []interface{}) interface{} { return IsCardType(a[0].(CardType)) }},
+	"IsUnitCard":     {kindFilter, nil, false, func(a []interface{}) interface{} { return IsUnitCard() }},
+	"IsTrigger":      {kindFilter, params(kindTrigger), false, func(a []interface{}) interface{} { return IsTrigger(a[0].(TriggerType)) }},
+	"NameContains":   {kindFilter, params(kindString), false, func(a []interface{}) interface{} { return NameContains(a[0].(string)) }},
+	"HasClan":        {kindFilter, params(kindString), false, func(a []interface{}) interface{} { return HasClan(a[0].(string)) }},
+	"HasNation":      {kindFilter, params(kindString), false, func(a []interface{}) interface{} { return HasNation(a[0].(string)) }},
+	"MatchAll":       {kindFilter, params(kindFilter), true, func(a []interface{}) interface{} { return MatchAll(filters(a)...) }},
+}
+
+func conditions(args []interface{}) []Condition {
+	result := make([]Condition, len(args))
+	for i, arg := range args {
+		result[i] = arg.(Condition)
+	}
+	return result
+}
+
+func effects(args []interface{}) []EffectAction {
+	result := make([]EffectAction, len(args))
+	for i, arg := range args {
+		result[i] = arg.(EffectAction)
+	}
+	return result
+}
+
+func filters(args []interface{}) []CardFilter {
+	result := make([]CardFilter, len(args))
+	for i, arg := range args {
+		result[i] = arg.(CardFilter)
+	}
+	return result
+}
+
+// scriptNames lists the names usable in card scripts for a kind of value.
+func scriptNames(kind scriptKind) []string {
+	names := []string{}
+	for name, function := range scriptFuncs {
+		if function.result == kind || (kind == kindSelector && function.result == kindTarget) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// decodeScriptValue decodes a JSON value of the expected kind.
+func decodeScriptValue(raw json.RawMessage, kind scriptKind) (interface{}, error) {
+	switch kind {
+	case kindInt:
+		var value int
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return nil, errors.New("expected a number, got " + string(raw))
+		}
+		return value, nil
+	case kindBool:
+		var value bool
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return nil, errors.New("expected true or false, got " + string(raw))
+		}
+		return value, nil
+	case kindCost:
+		var cost scriptCost
+		if err := strictUnmarshal(raw, &cost); err != nil {
+			return nil, err
+		}
+		return Cost(cost), nil
+	case kindString, kindZone, kindStat, kindRest, kindTrigger, kindCardType:
+		var value string
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return nil, errors.New("expected a " + string(kind) + ", got " + string(raw))
+		}
+		return decodeScriptEnum(kind, value)
+	case kindCounter:
+		// A plain number counts as Fixed
+		var value int
+		if json.Unmarshal(raw, &value) == nil {
+			return Fixed(value), nil
+		}
+	}
+	return decodeScriptCall(raw, kind)
+}
+
+func decodeScriptEnum(kind scriptKind, value string) (interface{}, error) {
+	oneOf := func(values ...string) (string, error) {
+		for _, v := range values {
+			if strings.EqualFold(v, value) {
+				return v, nil
+			}
+		}
+		return "", fmt.Errorf("unknown %s %q, expected one of %s", kind, value, strings.Join(values, ", "))
+	}
+
+	switch kind {
+	case kindZone:
+		zone, err := oneOf(string(ZoneField), string(ZoneHand), string(ZoneDeck), string(ZoneRideDeck), string(ZoneGDeck), string(ZoneDrop), string(ZoneDamage), string(ZoneSoul), string(ZoneBind), string(ZoneOrder), string(ZoneGuard), string(ZoneTrigger), string(ZoneCrest))
+		return Zone(zone), err
+	case kindStat:
+		stat, err := oneOf(string(StatPower), string(StatCritical), string(StatShield), string(StatDrive))
+		return Stat(stat), err
+	case kindRest:
+		rest, err := oneOf(string(RestBottom), string(RestTop), string(RestDrop), string(RestShuffle))
+		return RestPlacement(rest), err
+	case kindTrigger:
+		trigger, err := oneOf(string(TriggerCritical), string(TriggerDraw), string(TriggerFront), string(TriggerHeal), string(TriggerStand), string(TriggerOver))
+		return TriggerType(trigger), err
+	case kindCardType:
+		cardType, err := oneOf(string(CardTypeNormalUnit), string(CardTypeTriggerUnit), string(CardTypeGUnit), string(CardTypeNormalOrder), string(CardTypeSetOrder), string(CardTypeBlitzOrder), string(CardTypeCrest))
+		return CardType(cardType), err
+	}
+	return value, nil
+}
+
+// decodeScriptCall decodes ["Name", arguments...], or "Name" alone, into the value built by Name.
+func decodeScriptCall(raw json.RawMessage, kind scriptKind) (interface{}, error) {
+	var name string
+	var args []json.RawMessage
+	if json.Unmarshal(raw, &name) != nil {
+		var call []json.RawMessage
+		if err := json.Unmarshal(raw, &call); err != nil || len(call) == 0 || json.Unmarshal(call[0], &name) != nil {
+			return nil, errors.New("expected a " + string(kind) + " like [\"Name\", arguments...], got " + string(raw))
+		}
+		args = call[1:]
+	}
+
+	function, ok := scriptFuncs[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown %s %q, expected one of %s", kind, name, strings.Join(scriptNames(kind), ", "))
+	}
+	if function.result != kind && !(kind == kindSelector && function.result == kindTarget) {
+		return nil, fmt.Errorf("%s is a %s, expected a %s", name, function.result, kind)
+	}
+
+	switch {
+	case function.variadic && len(args) < len(function.params)-1:
+		return nil, fmt.Errorf("%s expects at least %d argument(s), got %d", name, len(function.params)-1, len(args))
+	case !function.variadic && len(args) != len(function.params):
+		return nil, fmt.Errorf("%s expects %d argument(s), got %d", name, len(function.params), len(args))
+	}
+
+	values := make([]interface{}, len(args))
+	for i, arg := range args {
+		param := function.params[min(i, len(function.params)-1)]
+		value, err := decodeScriptValue(arg, param)
+		if err != nil {
+			return nil, fmt.Errorf("%s argument %s: %w", name, strconv.Itoa(i+1), err)
+		}
+		values[i] = value
+	}
+
+	value := function.build(values)
+	if kind == kindSelector && function.result == kindTarget {
+		return value.(Target).Select(), nil
+	}
+	return value, nil
+}
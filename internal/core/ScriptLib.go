@@ -0,0 +1,28 @@
+package core
+
+// Hand-written card scripts, see CardScript.
+
+func init() {
+	RegisterCardScript(CardScript{Key: "Energy Generator", Zone: ZoneCrest, Abilities: energyGenerator})
+}
+
+// energyGenerator: [AUTO]:At the beginning of your ride phase, Energy Charge 3.
+// [ACT](1/Turn):COST [Energy Blast 7], draw a card.
+func energyGenerator() []Ability {
+	return []Ability{
+		{
+			Kind:        AbilityAUTO,
+			Description: "At the beginning of your ride phase, Energy Charge 3",
+			Timing:      PhaseTiming("START_", PhaseRide),
+			Condition:   IsTurnPlayer(),
+			Effect:      EnergyChargeEffect(3),
+		},
+		{
+			Kind:        AbilityACT,
+			Description: "COST [Energy Blast 7], draw a card",
+			Cost:        Cost{EnergyBlast: 7},
+			Effect:      DrawEffect(1),
+			Limit:       UsageLimit{Scope: LimitTurn},
+		},
+	}
+}
@@ -0,0 +1,158 @@
+package core
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+// CardFilter tells whether a card matches what an effect looks for ("a grade 3", "a trigger unit"...).
+type CardFilter func(card *Card) bool
+
+// AnyCard matches every card.
+func AnyCard() CardFilter {
+	return func(card *Card) bool {
+		return true
+	}
+}
+
+func IsGrade(grade int) CardFilter {
+	return func(card *Card) bool {
+		return card.Grade == grade
+	}
+}
+
+func GradeOrLess(grade int) CardFilter {
+	return func(card *Card) bool {
+		return card.Grade != NoGrade && card.Grade <= grade
+	}
+}
+
+func GradeOrGreater(grade int) CardFilter {
+	return func(card *Card) bool {
+		return card.Grade >= grade
+	}
+}
+
+func IsCardType(cardType CardType) CardFilter {
+	return func(card *Card) bool {
+		return card.CardType == cardType
+	}
+}
+
+// IsUnitCard matches normal, trigger and G units.
+func IsUnitCard() CardFilter {
+	return func(card *Card) bool {
+		return card.CardType.IsUnit()
+	}
+}
+
+func IsTrigger(triggerType TriggerType) CardFilter {
+	return func(card *Card) bool {
+		return card.Trigger.Type == triggerType
+	}
+}
+
+// NameContains matches cards whose name includes the text, "Blaster" matching "Blaster Blade".
+func NameContains(text string) CardFilter {
+	return func(card *Card) bool {
+		return strings.Contains(strings.ToLower(card.Name), strings.ToLower(text))
+	}
+}
+
+// HasClan matches cards belonging to the clan, case insensitive.
+func HasClan(clan string) CardFilter {
+	return func(card *Card) bool {
+		return containsFold(card.Clan, clan)
+	}
+}
+
+// HasNation matches cards belonging to the nation, case insensitive.
+func HasNation(nation string) CardFilter {
+	return func(card *Card) bool {
+		return containsFold(card.Nation, nation)
+	}
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(strings.TrimSpace(v), strings.TrimSpace(value)) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchAll combines filters, a card has to match every one of them.
+func MatchAll(filters ...CardFilter) CardFilter {
+	return func(card *Card) bool {
+		for _, filter := range filters {
+			if filter != nil && !filter(card) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// filterCards keeps the cards matching the filter, a nil filter matching everything.
+func filterCards(cards []*Card, filter CardFilter) []*Card {
+	result := []*Card{}
+	for _, card := range cards {
+		if card != nil && (filter == nil || filter(card)) {
+			result = append(result, card)
+		}
+	}
+	return result
+}
+
+// reveal shows the cards of a player to that player only, or to every player when public.
+func (party *Party) reveal(owner *Player, cards []*Card, public bool) {
+	if len(cards) == 0 {
+		return
+	}
+	ownerIndex := party.playerIndex(owner)
+	names := make([]string, len(cards))
+	for i, card := range cards {
+		names[i] = card.Name
+	}
+	visibility := "privately"
+	if public {
+		visibility = "publicly"
+	}
+	fmt.Printf("Reveal (%s): Player %d %s\n", visibility, ownerIndex, strings.Join(names, ", "))
+	party.log(LogEntry{Action: LogReveal, Player: ownerIndex, Cards: cardIDs(cards), Detail: visibility, Hidden: !public})
+
+	if party.Reveal == nil {
+		return
+	}
+	for i := range party.Players {
+		if public || i == ownerIndex {
+			party.Reveal(i, ownerIndex, cards)
+		}
+	}
+}
+
+// Shuffle shuffles the player's deck with the party's seeded generator, so a game can be replayed.
+func (party *Party) Shuffle(player *Player) {
+	if party.rand == nil {
+		party.rand = rand.New(rand.NewSource(0))
+	}
+	party.rand.Shuffle(len(player.MainDeck), func(i, j int) {
+		player.MainDeck[i], player.MainDeck[j] = player.MainDeck[j], player.MainDeck[i]
+	})
+	fmt.Printf("Shuffle: Player %d deck\n", party.playerIndex(player))
+	party.log(LogEntry{Action: LogShuffle, Player: party.playerIndex(player), Amount: len(player.MainDeck)})
+}
+
+// chooseOrder lets the player order cards one by one, e.g. before putting them on the bottom of the deck.
+func (party *Party) chooseOrder(player *Player, cards []*Card, prompt string) []*Card {
+	remaining := append([]*Card{}, cards...)
+	ordered := []*Card{}
+	for len(remaining) > 1 {
+		chosen := party.choose(player, Choice{Kind: ChoiceCard, Prompt: prompt, Options: cardOptions(remaining), Min: 1, Max: 1})
+		ordered = append(ordered, remaining[chosen[0]])
+		remaining = append(remaining[:chosen[0]], remaining[chosen[0]+1:]...)
+	}
+	return append(ordered, remaining...)
+}
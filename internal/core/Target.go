@@ -0,0 +1,215 @@
+package core
+
+import (
+	"strconv"
+)
+
+// TargetSide is whose cards a target can be.
+type TargetSide string
+
+const (
+	SideYours    TargetSide = "yours"
+	SideOpponent TargetSide = "opponent"
+	SideAny      TargetSide = "any"
+)
+
+// TargetChooser is the player making the choice.
+type TargetChooser string
+
+const (
+	// ChooserController is the player resolving the effect, the default.
+	ChooserController TargetChooser = "controller"
+	// ChooserOwner lets the owner of the cards choose ("your opponent chooses one of their rear-guards").
+	ChooserOwner TargetChooser = "owner"
+)
+
+// Target describes what an effect can be applied to: Count cards among the circles
+// (or the Zone) of a side, matching Filter. A mandatory target takes Count cards when
+// there are enough, UpTo lets the chooser take fewer, All takes every match without a choice.
+type Target struct {
+	Side    TargetSide
+	Zone    Zone
+	Circles []CircleID
+	Filter  CardFilter
+	Count   int
+	UpTo    bool
+	All     bool
+	Chooser TargetChooser
+	Prompt  string
+}
+
+// candidate is a card that can be targeted, with the player it belongs to.
+type candidate struct {
+	owner  *Player
+	circle CircleID
+	card   *Card
+}
+
+func YourUnits(count int) Target {
+	return Target{Side: SideYours, Circles: AllCircles, Count: count}
+}
+
+func YourRearGuards(count int) Target {
+	return Target{Side: SideYours, Circles: rearGuardCircles(), Count: count}
+}
+
+func YourVanguard() Target {
+	return Target{Side: SideYours, Circles: []CircleID{CircleVanguard}, Count: 1}
+}
+
+func OpponentUnits(count int) Target {
+	return Target{Side: SideOpponent, Circles: AllCircles, Count: count}
+}
+
+func OpponentRearGuards(count int) Target {
+	return Target{Side: SideOpponent, Circles: rearGuardCircles(), Count: count}
+}
+
+func OpponentFrontRow(count int) Target {
+	return Target{Side: SideOpponent, Circles: FrontRow, Count: count}
+}
+
+// CardsIn targets cards of one of your zones (hand, drop, soul...).
+func CardsIn(zone Zone, count int) Target {
+	return Target{Side: SideYours, Zone: zone, Count: count}
+}
+
+// AllOf turns the target into "all of them", no choice is made.
+func (target Target) AllOf() Target {
+	target.All = true
+	return target
+}
+
+// UpToCount turns the target into "choose up to Count".
+func (target Target) UpToCount() Target {
+	target.UpTo = true
+	return target
+}
+
+// Where restricts the target to cards matching the filter.
+func (target Target) Where(filter CardFilter) Target {
+	target.Filter = MatchAll(target.Filter, filter)
+	return target
+}
+
+// ChosenByOwner lets the owner of the targeted cards make the choice.
+func (target Target) ChosenByOwner() Target {
+	target.Chooser = ChooserOwner
+	return target
+}
+
+func rearGuardCircles() []CircleID {
+	circles := []CircleID{}
+	for _, id := range AllCircles {
+		if id != CircleVanguard {
+			circles = append(circles, id)
+		}
+	}
+	return circles
+}
+
+// sides returns the players whose cards can be targeted, from the controller's point of view.
+func (target Target) sides(party *Party, player *Player) []*Player {
+	opponent := party.opponent(player)
+	switch target.Side {
+	case SideOpponent:
+		if opponent == nil {
+			return nil
+		}
+		return []*Player{opponent}
+	case SideAny:
+		if opponent == nil {
+			return []*Player{player}
+		}
+		return []*Player{player, opponent}
+	}
+	return []*Player{player}
+}
+
+// Candidates lists every card the target can be, in circle order.
+func (target Target) Candidates(party *Party, player *Player) []*Card {
+	cards := []*Card{}
+	for _, candidate := range target.candidates(party, player) {
+		cards = append(cards, candidate.card)
+	}
+	return cards
+}
+
+func (target Target) candidates(party *Party, player *Player) []candidate {
+	result := []candidate{}
+	for _, owner := range target.sides(party, player) {
+		if target.Zone != "" && target.Zone != ZoneField {
+			if zone := owner.zone(target.Zone); zone != nil {
+				for _, card := range filterCards(*zone, target.Filter) {
+					result = append(result, candidate{owner: owner, card: card})
+				}
+			}
+			continue
+		}
+
+		circles := target.Circles
+		if len(circles) == 0 {
+			circles = AllCircles
+		}
+		for _, id := range owner.OccupiedCircles(circles) {
+			card := owner.Circle(id).TopCard
+			if target.Filter == nil || target.Filter(card) {
+				result = append(result, candidate{owner: owner, circle: id, card: card})
+			}
+		}
+	}
+	return result
+}
+
+// Select resolves the target into a CardSelector, asking the right player to choose.
+func (target Target) Select() CardSelector {
+	return func(party *Party, player *Player, source *Card) []*Card {
+		candidates := target.candidates(party, player)
+		if target.All {
+			cards := make([]*Card, len(candidates))
+			for i, candidate := range candidates {
+				cards[i] = candidate.card
+			}
+			return cards
+		}
+		if len(candidates) == 0 || target.Count <= 0 {
+			return nil
+		}
+
+		chooser := player
+		if target.Chooser == ChooserOwner && target.Side == SideOpponent {
+			chooser = candidates[0].owner
+		}
+
+		options := make([]string, len(candidates))
+		for i, candidate := range candidates {
+			label := ToString(candidate.card)
+			if candidate.circle != "" {
+				label = string(candidate.circle) + ": " + label
+			}
+			if candidate.owner != chooser {
+				label = "Opponent's " + label
+			}
+			options[i] = label
+		}
+
+		prompt := target.Prompt
+		if prompt == "" {
+			prompt = "Choose " + strconv.Itoa(target.Count) + " card(s)"
+			if target.UpTo {
+				prompt = "Choose up to " + strconv.Itoa(target.Count) + " card(s)"
+			}
+		}
+		min := target.Count
+		if target.UpTo {
+			min = 0
+		}
+
+		chosen := party.choose(chooser, Choice{Kind: ChoiceCard, Prompt: prompt, Options: options, Min: min, Max: target.Count})
+		cards := make([]*Card, len(chosen))
+		for i, index := range chosen {
+			cards[i] = candidates[index].card
+		}
+		return cards
+	}
+}
@@ -2,9 +2,13 @@ package core
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
@@ -23,15 +27,33 @@ type Client struct {
 	// Add channel to Client for Mulligan response
 	MulliganCh chan []int
 	OrderCh    chan string
+	ChoiceCh   chan []int
+	// Deck chosen in the lobby, validated before being stored
+	Deck     *Deck
+	DeckName string
+	// writeMutex serializes writes to Conn, the game and the lobby write from different goroutines
+	writeMutex sync.Mutex
+}
+
+// send writes a JSON message to the client. Every write to Conn goes through it.
+func (client *Client) send(msg interface{}) error {
+	client.writeMutex.Lock()
+	defer client.writeMutex.Unlock()
+	return client.Conn.WriteJSON(msg)
 }
 
 type Room struct {
 	ID      string
 	Clients map[string]*Client
-	Party   *Party
-	Mutex   sync.Mutex
+	// Seats keeps client IDs in join order, the order decks are given to InitParty
+	Seats []string
+	Party *Party
+	Mutex sync.Mutex
 }
 
+// DeckDirectory holds the decks players can pick with "select_deck".
+var DeckDirectory = "decks"
+
 var (
 	rooms     = make(map[string]*Room)
 	roomsLock sync.RWMutex
@@ -60,7 +82,7 @@ func handleWebSocket(w http.ResponseWriter, r *http.Request) {
 		clientID = uuid.New().String()
 	}
 
-	client := &Client{ID: clientID, Conn: conn, MulliganCh: make(chan []int), OrderCh: make(chan string)}
+	client := &Client{ID: clientID, Conn: conn, MulliganCh: make(chan []int), OrderCh: make(chan string), ChoiceCh: make(chan []int)}
 	defer func() {
 		handleQuitRoom(client)
 		conn.Close()
@@ -90,8 +112,43 @@ func handleWebSocket(w http.ResponseWriter, r *http.Request) {
 			}
 		case "quit_room":
 			handleQuitRoom(client)
+		case "list_decks":
+			handleListDecks(client)
+		case "select_deck":
+			name, _ := payload["name"].(string)
+			handleSelectDeck(client, name)
+		case "submit_deck":
+			format, _ := payload["format"].(string)
+			content, _ := payload["content"].(string)
+			name, _ := payload["name"].(string)
+			handleSubmitDeck(client, DeckFormat(format), content, name)
+		case "library_list":
+			handleLibraryList(client)
+		case "library_get":
+			name, _ := payload["name"].(string)
+			handleLibraryGet(client, name, intField(payload, "revision"))
+		case "library_save":
+			name, _ := payload["name"].(string)
+			format, _ := payload["format"].(string)
+			content, _ := payload["content"].(string)
+			note, _ := payload["note"].(string)
+			handleLibrarySave(client, name, stringsField(payload, "tags"), DeckFormat(format), content, note)
+		case "library_delete":
+			name, _ := payload["name"].(string)
+			handleLibraryDelete(client, name)
+		case "library_diff":
+			name, _ := payload["name"].(string)
+			handleLibraryDiff(client, name, intField(payload, "from"), intField(payload, "to"))
+		case "library_select":
+			name, _ := payload["name"].(string)
+			handleLibrarySelect(client, name, intField(payload, "revision"))
 		case "create_party":
-			handleCreateParty(client)
+			rules, err := rulesFromPayload(payload)
+			if err != nil {
+				client.send(map[string]string{"error": err.Error()})
+				continue
+			}
+			handleCreateParty(client, rules)
 		case "close_party":
 			handleCloseParty(client)
 		case "mulligan_response":
@@ -107,6 +164,19 @@ func handleWebSocket(w http.ResponseWriter, r *http.Request) {
 				default:
 				}
 			}
+		case "choice_response":
+			if indicesInt, ok := payload["indices"].([]interface{}); ok {
+				indices := []int{}
+				for _, v := range indicesInt {
+					if f, ok := v.(float64); ok {
+						indices = append(indices, int(f))
+					}
+				}
+				select {
+				case client.ChoiceCh <- indices:
+				default:
+				}
+			}
 		case "order_response":
 			if choice, ok := payload["choice"].(string); ok {
 				select {
@@ -144,17 +214,18 @@ func handleJoinRoom(client *Client, roomID string) {
 	roomsLock.RUnlock()
 
 	if !exists {
-		client.Conn.WriteJSON(map[string]string{"error": "Room not found"})
+		client.send(map[string]string{"error": "Room not found"})
 		return
 	}
 
 	room.Mutex.Lock()
 	room.Clients[client.ID] = client
+	room.Seats = append(room.Seats, client.ID)
 	client.RoomID = roomID
 	playerCount := len(room.Clients)
 	room.Mutex.Unlock()
 
-	client.Conn.WriteJSON(map[string]interface{}{"event": "room_joined", "room_id": roomID})
+	client.send(map[string]interface{}{"event": "room_joined", "room_id": roomID})
 	broadcast(room, map[string]interface{}{"event": "player_joined", "player_count": playerCount, "player_id": client.ID})
 }
 
@@ -168,6 +239,12 @@ func handleQuitRoom(client *Client) {
 	if exists {
 		room.Mutex.Lock()
 		delete(room.Clients, client.ID)
+		for i, id := range room.Seats {
+			if id == client.ID {
+				room.Seats = append(room.Seats[:i], room.Seats[i+1:]...)
+				break
+			}
+		}
 		count := len(room.Clients)
 		room.Mutex.Unlock()
 		if count == 0 {
@@ -181,7 +258,29 @@ func handleQuitRoom(client *Client) {
 	client.RoomID = ""
 }
 
-func handleCreateParty(client *Client) {
+// rulesFromPayload reads the "format" preset and its optional overrides.
+func rulesFromPayload(payload map[string]interface{}) (Rules, error) {
+	rules := DefaultRules()
+	if format, ok := payload["format"].(string); ok && format != "" {
+		preset, exists := RulesFor(format)
+		if !exists {
+			return rules, errors.New("Unknown format: " + format)
+		}
+		rules = preset
+	}
+	if v, ok := payload["skip_first_draw"].(bool); ok {
+		rules.SkipFirstDraw = v
+	}
+	if v, ok := payload["no_first_turn_battle"].(bool); ok {
+		rules.NoFirstTurnBattle = v
+	}
+	if _, ok := payload["second_player_energy"].(float64); ok {
+		rules.SecondPlayerEnergy = intField(payload, "second_player_energy")
+	}
+	return rules, nil
+}
+
+func handleCreateParty(client *Client, rules Rules) {
 	roomsLock.RLock()
 	room, exists := rooms[client.RoomID]
 	roomsLock.RUnlock()
@@ -193,26 +292,45 @@ func handleCreateParty(client *Client) {
 
 	if len(room.Clients) < 2 {
 		room.Mutex.Unlock()
-		client.Conn.WriteJSON(map[string]string{"error": "Need at least 2 players"})
+		client.send(map[string]string{"error": "Need at least 2 players"})
 		return
 	}
 
 	// Make a copy of clients for the party to avoid lock issues during async execution
+	// Seat order decides which deck goes to which player
 	clientsList := []*Client{}
-	for _, c := range room.Clients {
+	decks := []*Deck{}
+	missing := []string{}
+	for _, id := range room.Seats {
+		c := room.Clients[id]
+		if c.Deck == nil {
+			missing = append(missing, c.ID)
+			continue
+		}
 		clientsList = append(clientsList, c)
+		decks = append(decks, c.Deck.Clone())
 	}
 	room.Mutex.Unlock()
 
+	if len(missing) > 0 {
+		client.send(map[string]interface{}{"error": "All players must select a deck", "players": missing})
+		return
+	}
+
 	go func() {
-		deck1, err1 := ParseDeckFile("decks/KT_Starter.md")
-		deck2, err2 := ParseDeckFile("decks/LM_Starter.md")
-		if err1 != nil || err2 != nil {
-			client.Conn.WriteJSON(map[string]string{"error": "Failed to load decks"})
-			return
+		party := InitParty(decks)
+		// Each client sees the log without the hidden cards of the other player, from the
+		// first setup move. clientsList is swapped along with the players once the order is decided.
+		party.Logged = func(entry LogEntry) {
+			for i, c := range clientsList {
+				c.send(map[string]interface{}{
+					"event": "log",
+					"entry": entry.VisibleTo(i),
+				})
+			}
 		}
 
-		party := InitParty([]*Deck{deck1, deck2})
+		party.Rules = rules
 		InitGame(party, "")
 
 		// Set party on room
@@ -227,7 +345,7 @@ func handleCreateParty(client *Client) {
 			func(r0, r1 int) {
 				// Send roll results
 				for i, c := range clientsList {
-					c.Conn.WriteJSON(map[string]interface{}{
+					c.send(map[string]interface{}{
 						"event":      "dice_roll",
 						"rolls":      []int{r0, r1},
 						"your_index": i,
@@ -240,7 +358,7 @@ func handleCreateParty(client *Client) {
 					return "first"
 				}
 				winnerClient := clientsList[winnerIndex]
-				winnerClient.Conn.WriteJSON(map[string]interface{}{"event": "ask_first_second"})
+				winnerClient.send(map[string]interface{}{"event": "ask_first_second"})
 
 				choice := <-winnerClient.OrderCh
 				return choice
@@ -252,8 +370,8 @@ func handleCreateParty(client *Client) {
 		}
 
 		// Notify Turn Order
-		clientsList[0].Conn.WriteJSON(map[string]string{"event": "turn_order", "msg": "You are going FIRST"})
-		clientsList[1].Conn.WriteJSON(map[string]string{"event": "turn_order", "msg": "You are going SECOND"})
+		clientsList[0].send(map[string]string{"event": "turn_order", "msg": "You are going FIRST"})
+		clientsList[1].send(map[string]string{"event": "turn_order", "msg": "You are going SECOND"})
 		time.Sleep(1 * time.Second)
 
 		// 2. Perform Mulligan (Parallel)
@@ -269,7 +387,7 @@ func handleCreateParty(client *Client) {
 				handData = append(handData, ToString(c))
 			}
 
-			targetClient.Conn.WriteJSON(map[string]interface{}{
+			targetClient.send(map[string]interface{}{
 				"event": "request_mulligan",
 				"hand":  handData,
 			})
@@ -286,21 +404,233 @@ func handleCreateParty(client *Client) {
 			for _, card := range player.Hand {
 				handData = append(handData, ToString(card))
 			}
-			c.Conn.WriteJSON(map[string]interface{}{
+			c.send(map[string]interface{}{
 				"event": "update_hand",
 				"hand":  handData,
 			})
 		}
 
+		// In-game decisions are forwarded to the player's client
+		party.Decide = func(playerIndex int, choice Choice) []int {
+			if playerIndex < 0 || playerIndex >= len(clientsList) {
+				return []int{}
+			}
+			targetClient := clientsList[playerIndex]
+			targetClient.send(map[string]interface{}{
+				"event":  "request_choice",
+				"choice": choice,
+			})
+			return <-targetClient.ChoiceCh
+		}
+		party.Reveal = func(viewerIndex int, ownerIndex int, cards []*Card) {
+			if viewerIndex < 0 || viewerIndex >= len(clientsList) || ownerIndex < 0 || ownerIndex >= len(clientsList) {
+				return
+			}
+			cardData := []string{}
+			for _, card := range cards {
+				cardData = append(cardData, ToString(card))
+			}
+			clientsList[viewerIndex].send(map[string]interface{}{
+				"event": "reveal",
+				"owner": clientsList[ownerIndex].ID,
+				"cards": cardData,
+			})
+		}
+
 		broadcast(room, map[string]interface{}{"event": "game_started", "turn": party.Turn})
 		PrintParty(party) // Log on server
 
-		// Start the first turn
-		party.StartTurn()
-		PrintParty(party) // Log again to see draw
+		// Play turns until a player loses
+		party.Run()
+		PrintParty(party)
+
+		winner := ""
+		if party.Winner >= 0 && party.Winner < len(clientsList) {
+			winner = clientsList[party.Winner].ID
+		}
+		// The record lets the players replay the game, see Replay
+		broadcast(room, map[string]interface{}{
+			"event":  "game_over",
+			"winner": winner,
+			"draw":   winner == "",
+			"reason": party.EndReason,
+			"record": party.Record(),
+		})
 	}()
 }
 
+func handleListDecks(client *Client) {
+	entries, err := os.ReadDir(DeckDirectory)
+	if err != nil {
+		client.send(map[string]string{"error": "Failed to list decks"})
+		return
+	}
+
+	names := []string{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		switch strings.ToLower(filepath.Ext(entry.Name())) {
+		case ".md", ".txt", ".json", ".code", ".vgcode":
+			names = append(names, entry.Name())
+		}
+	}
+	client.send(map[string]interface{}{"event": "deck_list", "decks": names})
+}
+
+// handleSelectDeck picks one of the decks stored in DeckDirectory.
+func handleSelectDeck(client *Client, name string) {
+	if name == "" || filepath.Base(name) != name {
+		client.send(map[string]string{"error": "Invalid deck name"})
+		return
+	}
+
+	deck, err := LoadDeckFile(filepath.Join(DeckDirectory, name), DeckParseOptions{CollectAll: true})
+	acceptDeck(client, name, deck, err)
+}
+
+// handleSubmitDeck parses a deck list sent by the client.
+func handleSubmitDeck(client *Client, format DeckFormat, content string, name string) {
+	if name == "" {
+		name = "Custom deck"
+	}
+
+	deck, err := ParseDeckFormat(strings.NewReader(content), format, DeckParseOptions{CollectAll: true})
+	acceptDeck(client, name, deck, err)
+}
+
+// acceptDeck validates the deck and keeps it on the client, or reports every problem found.
+func acceptDeck(client *Client, name string, deck *Deck, err error) {
+	if err == nil {
+		if violations := ValidateDeck(deck); len(violations) > 0 {
+			err = violations
+		}
+	}
+	if err != nil {
+		client.send(map[string]interface{}{
+			"event":  "deck_rejected",
+			"name":   name,
+			"errors": strings.Split(err.Error(), "\n"),
+		})
+		return
+	}
+
+	roomsLock.RLock()
+	room, exists := rooms[client.RoomID]
+	roomsLock.RUnlock()
+
+	// handleCreateParty reads the decks of the room's clients under the room lock
+	if exists {
+		room.Mutex.Lock()
+	}
+	client.Deck = deck
+	client.DeckName = name
+	if exists {
+		room.Mutex.Unlock()
+	}
+	client.send(map[string]interface{}{"event": "deck_selected", "name": name})
+
+	if exists {
+		broadcast(room, map[string]interface{}{"event": "player_deck_ready", "player_id": client.ID, "name": name})
+	}
+}
+
+func handleLibraryList(client *Client) {
+	decks, err := Library.List(client.ID)
+	if err != nil {
+		client.send(map[string]string{"error": err.Error()})
+		return
+	}
+	client.send(map[string]interface{}{"event": "library_list", "decks": decks})
+}
+
+func handleLibraryGet(client *Client, name string, revision int) {
+	deck, rev, err := Library.Get(client.ID, name, revision)
+	if err != nil {
+		client.send(map[string]string{"error": err.Error()})
+		return
+	}
+	client.send(map[string]interface{}{
+		"event":     "library_deck",
+		"name":      deck.Name,
+		"tags":      deck.Tags,
+		"revisions": len(deck.Revisions),
+		"revision":  rev,
+	})
+}
+
+// handleLibrarySave stores a new revision, construction rule violations are reported but do not block saving.
+func handleLibrarySave(client *Client, name string, tags []string, format DeckFormat, content string, note string) {
+	deck, err := ParseDeckFormat(strings.NewReader(content), format, DeckParseOptions{CollectAll: true})
+	if err != nil {
+		client.send(map[string]interface{}{"event": "library_rejected", "name": name, "errors": strings.Split(err.Error(), "\n")})
+		return
+	}
+
+	rev, err := Library.Save(client.ID, name, tags, deck, note)
+	if err != nil {
+		client.send(map[string]string{"error": err.Error()})
+		return
+	}
+
+	warnings := []string{}
+	for _, violation := range ValidateDeck(deck) {
+		warnings = append(warnings, violation.String())
+	}
+	client.send(map[string]interface{}{"event": "library_saved", "name": name, "revision": rev.Number, "warnings": warnings})
+}
+
+func handleLibraryDelete(client *Client, name string) {
+	if err := Library.Delete(client.ID, name); err != nil {
+		client.send(map[string]string{"error": err.Error()})
+		return
+	}
+	client.send(map[string]interface{}{"event": "library_deleted", "name": name})
+}
+
+func handleLibraryDiff(client *Client, name string, from int, to int) {
+	diff, err := Library.Diff(client.ID, name, from, to)
+	if err != nil {
+		client.send(map[string]string{"error": err.Error()})
+		return
+	}
+	client.send(map[string]interface{}{"event": "library_diff", "name": name, "from": from, "to": to, "diff": diff})
+}
+
+// handleLibrarySelect uses a saved revision as the client's deck for the next party.
+func handleLibrarySelect(client *Client, name string, revision int) {
+	_, rev, err := Library.Get(client.ID, name, revision)
+	if err != nil {
+		client.send(map[string]string{"error": err.Error()})
+		return
+	}
+
+	deck, err := DeckFromJSON(rev.List, DeckParseOptions{CollectAll: true})
+	acceptDeck(client, name, deck, err)
+}
+
+func intField(payload map[string]interface{}, key string) int {
+	if f, ok := payload[key].(float64); ok {
+		return int(f)
+	}
+	return 0
+}
+
+func stringsField(payload map[string]interface{}, key string) []string {
+	values, ok := payload[key].([]interface{})
+	if !ok {
+		return nil
+	}
+	result := []string{}
+	for _, v := range values {
+		if str, ok := v.(string); ok {
+			result = append(result, str)
+		}
+	}
+	return result
+}
+
 func handleCloseParty(client *Client) {
 	roomsLock.RLock()
 	room, exists := rooms[client.RoomID]
@@ -318,6 +648,6 @@ func broadcast(room *Room, msg interface{}) {
 	room.Mutex.Lock()
 	defer room.Mutex.Unlock()
 	for _, c := range room.Clients {
-		c.Conn.WriteJSON(msg)
+		c.send(msg)
 	}
 }
@@ -0,0 +1,319 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// Zone names a card zone of a player, ZoneField standing for the circles.
+type Zone string
+
+const (
+	ZoneField    Zone = "field"
+	ZoneHand     Zone = "hand"
+	ZoneDeck     Zone = "deck"
+	ZoneRideDeck Zone = "ride deck"
+	ZoneGDeck    Zone = "g deck"
+	ZoneDrop     Zone = "drop"
+	ZoneDamage   Zone = "damage"
+	ZoneSoul     Zone = "soul"
+	ZoneBind     Zone = "bind"
+	ZoneOrder    Zone = "order"
+	ZoneGuard    Zone = "guardian circle"
+	ZoneTrigger  Zone = "trigger"
+	ZoneCrest    Zone = "crest"
+	// ZoneRemoved holds no card, cards removed from the game are only logged as going there
+	ZoneRemoved Zone = "removed"
+)
+
+// Zone-move timings, emitted with the moved card as subject.
+const (
+	TimingPlaced          = "WHEN_PLACED"
+	TimingRetired         = "WHEN_RETIRED"
+	TimingBound           = "WHEN_BOUND"
+	TimingLocked          = "WHEN_LOCKED"
+	TimingUnlocked        = "WHEN_UNLOCKED"
+	TimingReturnedToHand  = "WHEN_RETURNED_TO_HAND"
+	TimingPutIntoDeck     = "WHEN_PUT_INTO_DECK"
+	TimingSoulCharged     = "WHEN_SOUL_CHARGED"
+	TimingCounterCharged  = "WHEN_COUNTER_CHARGED"
+	TimingPutIntoDropZone = "WHEN_PUT_INTO_DROP_ZONE"
+	TimingPutIntoHand     = "WHEN_PUT_INTO_HAND"
+	TimingPutIntoSoul     = "WHEN_PUT_INTO_SOUL"
+	TimingRide            = "WHEN_RIDDEN"
+)
+
+// zone returns the card list of a zone, nil for the field.
+func (player *Player) zone(zone Zone) *[]*Card {
+	switch zone {
+	case ZoneHand:
+		return &player.Hand
+	case ZoneDeck:
+		return &player.MainDeck
+	case ZoneRideDeck:
+		return &player.RideDeck
+	case ZoneGDeck:
+		return &player.GDeck
+	case ZoneDrop:
+		return &player.DropZone
+	case ZoneDamage:
+		return &player.DamageZone
+	case ZoneSoul:
+		return &player.Vanguard.Soul
+	case ZoneBind:
+		return &player.BindZone
+	case ZoneOrder:
+		return &player.OrderZone
+	case ZoneGuard:
+		return &player.GuardZone
+	case ZoneTrigger:
+		return &player.TriggerZone
+	case ZoneCrest:
+		return &player.CrestZone
+	}
+	return nil
+}
+
+var cardZones = []Zone{ZoneHand, ZoneDeck, ZoneRideDeck, ZoneGDeck, ZoneDrop, ZoneDamage, ZoneSoul, ZoneBind, ZoneOrder, ZoneGuard, ZoneTrigger, ZoneCrest}
+
+// Locate finds the zone holding the card, and its circle when it is on the field.
+func (player *Player) Locate(card *Card) (Zone, CircleID, bool) {
+	if id, ok := player.CircleOf(card); ok {
+		return ZoneField, id, true
+	}
+	for _, zone := range cardZones {
+		for _, c := range *player.zone(zone) {
+			if c == card {
+				return zone, "", true
+			}
+		}
+	}
+	return "", "", false
+}
+
+// ownerOf returns the player holding the card in one of their zones.
+func (party *Party) ownerOf(card *Card) *Player {
+	for i := range party.Players {
+		if _, _, ok := party.Players[i].Locate(card); ok {
+			return &party.Players[i]
+		}
+	}
+	return nil
+}
+
+// takeCard removes the card from wherever it is, the field included, and returns its owner
+// with the zone and circle it was in. A unit leaving the field becomes a new instance, see leaveField.
+func (party *Party) takeCard(card *Card) (*Player, Zone, CircleID) {
+	owner := party.ownerOf(card)
+	if owner == nil {
+		return nil, "", ""
+	}
+	zone, id, _ := owner.Locate(card)
+	if zone == ZoneField {
+		owner.Circle(id).TopCard = nil
+		party.leaveField(card)
+	} else {
+		removeFromZone(owner.zone(zone), card)
+		if zone == ZoneGuard {
+			party.leaveField(card)
+		}
+	}
+	card.Locked = false
+	card.FaceDown = false
+	return owner, zone, id
+}
+
+// Retire puts a rear-guard into its owner's drop zone.
+func (party *Party) Retire(card *Card) bool {
+	owner := party.ownerOf(card)
+	if owner == nil {
+		return false
+	}
+	if zone, id, _ := owner.Locate(card); zone != ZoneField || id == CircleVanguard {
+		return false
+	}
+	if !party.replace(&PendingAction{Action: ActionRetire, Player: owner, Card: card}) {
+		return false
+	}
+	_, _, id := party.takeCard(card)
+	owner.DropZone = append(owner.DropZone, card)
+	fmt.Printf("Retire: %s\n", card.Name)
+	party.logMove(owner, card, ZoneField, ZoneDrop, id)
+	party.emit(TimingRetired, card)
+	party.emit(TimingPutIntoDropZone, card)
+	return true
+}
+
+// Bind puts the card into its owner's bind zone, face down if asked.
+func (party *Party) Bind(card *Card, faceDown bool) bool {
+	owner, from, id := party.takeCard(card)
+	if owner == nil {
+		return false
+	}
+	card.FaceDown = faceDown
+	owner.BindZone = append(owner.BindZone, card)
+	fmt.Printf("Bind: %s\n", card.Name)
+	party.logMove(owner, card, from, ZoneBind, id)
+	party.emit(TimingBound, card)
+	return true
+}
+
+// Lock turns a rear-guard face down: it cannot attack, boost, intercept nor use its abilities
+// until it is unlocked, at the latest at the end of its owner's turn.
+func (party *Party) Lock(card *Card) bool {
+	owner := party.ownerOf(card)
+	if owner == nil || card.Locked {
+		return false
+	}
+	if zone, id, _ := owner.Locate(card); zone != ZoneField || id == CircleVanguard {
+		return false
+	}
+	card.Locked = true
+	fmt.Printf("Lock: %s\n", card.Name)
+	party.logCard(LogLock, owner, card, "")
+	party.emit(TimingLocked, card)
+	return true
+}
+
+func (party *Party) Unlock(card *Card) bool {
+	if card == nil || !card.Locked {
+		return false
+	}
+	card.Locked = false
+	fmt.Printf("Unlock: %s\n", card.Name)
+	party.logCard(LogUnlock, party.ownerOf(card), card, "")
+	party.emit(TimingUnlocked, card)
+	return true
+}
+
+// unlockUnits unlocks the player's locked units at the end of their turn.
+func (party *Party) unlockUnits(player *Player) {
+	for _, id := range player.OccupiedCircles(AllCircles) {
+		party.Unlock(player.Circle(id).TopCard)
+	}
+}
+
+// ReturnToHand puts the card into its owner's hand.
+func (party *Party) ReturnToHand(card *Card) bool {
+	owner, from, id := party.takeCard(card)
+	if owner == nil {
+		return false
+	}
+	owner.Hand = append(owner.Hand, card)
+	fmt.Printf("Return to hand: %s\n", card.Name)
+	party.logMove(owner, card, from, ZoneHand, id)
+	party.emit(TimingReturnedToHand, card)
+	return true
+}
+
+// PutIntoDeck puts the card on the top or at the bottom of its owner's deck.
+func (party *Party) PutIntoDeck(card *Card, bottom bool) bool {
+	owner, from, id := party.takeCard(card)
+	if owner == nil {
+		return false
+	}
+	if bottom {
+		owner.MainDeck = append(owner.MainDeck, card)
+	} else {
+		owner.MainDeck = append([]*Card{card}, owner.MainDeck...)
+	}
+	fmt.Printf("Put into deck: %s\n", card.Name)
+	party.logMove(owner, card, from, ZoneDeck, id)
+	party.emit(TimingPutIntoDeck, card)
+	return true
+}
+
+// SoulCharge puts the top cards of the player's deck into their vanguard's soul.
+func (party *Party) SoulCharge(player *Player, count int) int {
+	charged := 0
+	for ; charged < count && len(player.MainDeck) > 0; charged++ {
+		card := player.MainDeck[0]
+		player.MainDeck = player.MainDeck[1:]
+		player.Vanguard.Soul = append(player.Vanguard.Soul, card)
+		fmt.Printf("Soul Charge: %s\n", card.Name)
+		party.logMove(player, card, ZoneDeck, ZoneSoul, "")
+		party.emit(TimingSoulCharged, card)
+	}
+	return charged
+}
+
+// CounterCharge turns face down damage face up, chosen by the player.
+func (party *Party) CounterCharge(player *Player, count int) int {
+	faceDown := []*Card{}
+	for _, card := range player.DamageZone {
+		if card.FaceDown {
+			faceDown = append(faceDown, card)
+		}
+	}
+	if count > len(faceDown) {
+		count = len(faceDown)
+	}
+
+	chosen := party.choose(player, Choice{Kind: ChoiceCard, Prompt: "Counter Charge " + strconv.Itoa(count), Options: cardOptions(faceDown), Min: count, Max: count})
+	for _, index := range chosen {
+		faceDown[index].FaceDown = false
+		fmt.Printf("Counter Charge: %s\n", faceDown[index].Name)
+		party.logCard(LogFlip, player, faceDown[index], "face up")
+		party.emit(TimingCounterCharged, faceDown[index])
+	}
+	return len(chosen)
+}
+
+// SuperiorCall places a unit from any zone of its owner on a rear-guard circle, standing.
+// A unit already on that circle is retired, the call fails when it cannot be.
+func (party *Party) SuperiorCall(card *Card, id CircleID) error {
+	owner := party.ownerOf(card)
+	switch {
+	case owner == nil:
+		return errors.New("card is not in a zone")
+	case !card.CardType.IsUnit():
+		return errors.New(card.Name + " is not a unit")
+	case id == CircleVanguard || owner.Circle(id) == nil:
+		return errors.New("cannot call to " + string(id))
+	}
+
+	// The unit in place may be protected, the call then does not happen
+	if previous := owner.Circle(id).TopCard; previous != nil && previous != card && !party.Retire(previous) {
+		return errors.New(previous.Name + " cannot be retired from " + string(id))
+	}
+	_, from, _ := party.takeCard(card)
+	card.Rested = false
+	owner.Circle(id).TopCard = card
+	fmt.Printf("Call: %s to %s\n", card.Name, id)
+	party.log(LogEntry{Action: LogCall, Player: party.playerIndex(owner), Card: card.ID, CardName: card.Name, From: from, To: ZoneField, Circle: id})
+	party.emit(TimingPlaced, card)
+	return nil
+}
+
+// MoveCard puts the card into a zone of its owner, at the end of it
+// (the top of the deck), and emits the matching timing.
+func (party *Party) MoveCard(card *Card, to Zone) bool {
+	switch to {
+	case ZoneBind:
+		return party.Bind(card, false)
+	case ZoneDeck:
+		return party.PutIntoDeck(card, false)
+	case ZoneField, "":
+		return false
+	}
+
+	owner, from, id := party.takeCard(card)
+	if owner == nil {
+		return false
+	}
+	zone := owner.zone(to)
+	*zone = append(*zone, card)
+	fmt.Printf("Move: %s to %s\n", card.Name, to)
+	party.logMove(owner, card, from, to, id)
+
+	switch to {
+	case ZoneHand:
+		party.emit(TimingPutIntoHand, card)
+	case ZoneDrop:
+		party.emit(TimingPutIntoDropZone, card)
+	case ZoneSoul:
+		party.emit(TimingPutIntoSoul, card)
+	}
+	return true
+}
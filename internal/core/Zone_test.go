@@ -0,0 +1,40 @@
+package core
+
+import "testing"
+
+// Superior calling onto a protected rear-guard fails and leaves both cards where they were.
+func TestSuperiorCallOntoProtectedRearGuard(t *testing.T) {
+	for _, protect := range []bool{false, true} {
+		party, _ := emptyBoard(t)
+		player := &party.Players[0]
+		previous := player.Rear1.TopCard
+		called := player.Hand[0]
+		if protect {
+			CannotBeRetiredEffect(ThisCard())(party, player, previous)
+		}
+
+		err := party.SuperiorCall(called, CircleRear1)
+
+		if protect {
+			if err == nil {
+				t.Error("call onto a protected rear-guard succeeded")
+			}
+			if player.Rear1.TopCard != previous || party.ownerOf(previous) != player {
+				t.Error("protected rear-guard left its circle")
+			}
+			if zone, _, _ := player.Locate(called); zone != ZoneHand {
+				t.Errorf("called card is in %q, want it back in hand", zone)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if player.Rear1.TopCard != called {
+			t.Error("called card is not on the circle")
+		}
+		if zone, _, _ := player.Locate(previous); zone != ZoneDrop {
+			t.Errorf("previous rear-guard is in %q, want the drop zone", zone)
+		}
+	}
+}
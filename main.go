@@ -1,7 +1,10 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"os"
+	"path/filepath"
 	"vg_core_go/internal/core"
 )
 
@@ -27,6 +30,67 @@ func defaultGame() {
 }
 
 func main() {
+	cards := flag.String("cards", "", "card database files, separated by '"+string(os.PathListSeparator)+"' (later files override earlier ones)")
+	scripts := flag.String("scripts", "", "card script files or directories, separated by '"+string(os.PathListSeparator)+"' (default $"+core.CardScriptsEnv+")")
+	listScripts := flag.Bool("list-scripts", false, "list the cards with a hand-written script and exit")
+	verifyScripts := flag.Bool("verify-scripts", false, "run every scripted ability on a sample board and exit")
+	replay := flag.String("replay", "", "replay a recorded game file, check it is reproduced exactly and exit")
+	flag.Parse()
+
+	if *cards != "" {
+		db, err := core.LoadCardDatabase(filepath.SplitList(*cards)...)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		for _, conflict := range db.Conflicts {
+			fmt.Println("Card database conflict: " + conflict.String())
+		}
+		core.SetCardDatabase(db)
+	}
+
+	scriptPaths := core.CardScriptPaths()
+	if *scripts != "" {
+		scriptPaths = filepath.SplitList(*scripts)
+	}
+	if err := core.LoadCardScripts(scriptPaths...); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	if *listScripts {
+		for _, key := range core.ScriptedCards() {
+			fmt.Println(key)
+		}
+		return
+	}
+	if *verifyScripts {
+		errs := core.VerifyCardScripts()
+		for _, err := range errs {
+			fmt.Println(err)
+		}
+		if len(errs) > 0 {
+			os.Exit(1)
+		}
+		fmt.Println(len(core.ScriptedCards()), "card script(s) verified")
+		return
+	}
+
+	if *replay != "" {
+		record, err := core.LoadGameRecord(*replay)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		party, err := core.Replay(record, nil)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		fmt.Println(len(party.Decisions), "decision(s) replayed, winner: Player", party.Winner)
+		return
+	}
+
 	core.StartServer("8080")
 	// defaultGame()
 }